@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/config"
+	"github.com/dormitory-life/auth/internal/constants"
+	"github.com/dormitory-life/auth/internal/jwtkeys"
+)
+
+func runKeys(subcommand string, args []string) error {
+	switch subcommand {
+	case "generate":
+		return generateKey(args)
+	case "promote":
+		return promoteKey(args)
+	case "list":
+		return listKeys(args)
+	default:
+		return fmt.Errorf("unknown keys subcommand %q", subcommand)
+	}
+}
+
+func generateKey(args []string) error {
+	flags := flag.NewFlagSet("keys generate", flag.ExitOnError)
+	configPath := flags.String("config", "configs/config.yaml", "path to the service config")
+	algorithm := flags.String("alg", jwtkeys.AlgorithmES256, "signing algorithm: RS256, ES256 or EdDSA")
+	dir := flags.String("dir", "keys", "directory for the private key file")
+	flags.Parse(args)
+
+	key, keyPEM, err := jwtkeys.GenerateKey(*algorithm)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create key directory: %w", err)
+	}
+
+	keyPath := filepath.Join(*dir, key.Id+".pem")
+	if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+		return fmt.Errorf("failed to write private key: %w", err)
+	}
+
+	err = config.UpdateJWTConfig(*configPath, func(cfg *config.JWTConfig) error {
+		migrateLegacyKey(cfg)
+
+		cfg.Keys = append(cfg.Keys, config.JWTKeyConfig{
+			Id:             key.Id,
+			Algorithm:      *algorithm,
+			PrivateKeyPath: keyPath,
+		})
+
+		return validateKeys(cfg)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(key.Id)
+	return nil
+}
+
+func promoteKey(args []string) error {
+	flags := flag.NewFlagSet("keys promote", flag.ExitOnError)
+	configPath := flags.String("config", "configs/config.yaml", "path to the service config")
+	id := flags.String("id", "", "id of the key to promote")
+	retireAfter := flags.Duration("retire-after", 0, "how long the previous key keeps verifying tokens (default: the longest refresh token lifetime)")
+	flags.Parse(args)
+
+	if *id == "" {
+		return fmt.Errorf("-id is required")
+	}
+
+	return config.UpdateJWTConfig(*configPath, func(cfg *config.JWTConfig) error {
+		migrateLegacyKey(cfg)
+
+		if *retireAfter <= 0 {
+			*retireAfter = longestRefreshTokenTTL(cfg)
+		}
+
+		found := false
+		for i := range cfg.Keys {
+			key := &cfg.Keys[i]
+
+			switch {
+			case key.Id == *id:
+				if key.Active {
+					return fmt.Errorf("key %q is already active", *id)
+				}
+
+				key.Active = true
+				key.NotAfter = time.Time{}
+				found = true
+
+			case key.Active:
+				key.Active = false
+				key.NotAfter = time.Now().UTC().Add(*retireAfter).Truncate(time.Second)
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("key %q not found", *id)
+		}
+
+		return validateKeys(cfg)
+	})
+}
+
+func listKeys(args []string) error {
+	flags := flag.NewFlagSet("keys list", flag.ExitOnError)
+	configPath := flags.String("config", "configs/config.yaml", "path to the service config")
+	flags.Parse(args)
+
+	cfg, err := config.ParseConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	migrateLegacyKey(&cfg.JWT)
+
+	for _, key := range cfg.JWT.Keys {
+		state := "next"
+		switch {
+		case key.Active:
+			state = "active"
+		case !key.NotAfter.IsZero():
+			state = "retired until " + key.NotAfter.Format(time.RFC3339)
+		}
+
+		fmt.Printf("%q\t%s\t%s\n", key.Id, key.Algorithm, state)
+	}
+
+	return nil
+}
+
+// migrateLegacyKey moves the single-key settings into the keys list so the
+// key can take part in rotation. Its id is kept, so tokens signed before the
+// migration stay valid.
+func migrateLegacyKey(cfg *config.JWTConfig) {
+	if len(cfg.Keys) > 0 {
+		return
+	}
+
+	algorithm := cfg.Algorithm
+	if algorithm == "" {
+		algorithm = jwtkeys.AlgorithmHS256
+	}
+
+	cfg.Keys = []config.JWTKeyConfig{{
+		Id:             cfg.KeyId,
+		Algorithm:      algorithm,
+		Secret:         cfg.Secret,
+		PrivateKeyPath: cfg.PrivateKeyPath,
+		Active:         true,
+	}}
+
+	cfg.Secret = ""
+	cfg.Algorithm = ""
+	cfg.KeyId = ""
+	cfg.PrivateKeyPath = ""
+}
+
+func longestRefreshTokenTTL(cfg *config.JWTConfig) time.Duration {
+	longest := cfg.RefreshTokenTTL
+	if longest <= 0 {
+		longest = constants.RefreshTokenTTL
+	}
+
+	for _, client := range cfg.Clients {
+		longest = max(longest, client.RefreshTokenTTL)
+	}
+
+	return longest
+}
+
+func validateKeys(cfg *config.JWTConfig) error {
+	if _, err := jwtkeys.LoadKeyring(*cfg); err != nil {
+		return fmt.Errorf("resulting key configuration is invalid: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `usage: authctl <command> <subcommand> [flags]
+
+commands:
+  keys generate   generate a new signing key and add it to the config as inactive
+  keys promote    make a key the active signing key and retire the previous one
+  keys list       list configured signing keys
+  users set-role  change the role of a user, e.g. to bootstrap a dormitory admin or a super_admin
+`
+
+func main() {
+	if len(os.Args) < 3 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keys":
+		err = runKeys(os.Args[2], os.Args[3:])
+	case "users":
+		err = runUsers(os.Args[2], os.Args[3:])
+	default:
+		err = fmt.Errorf("unknown command %q", os.Args[1])
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "authctl:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"slices"
+
+	"github.com/dormitory-life/auth/internal/config"
+	"github.com/dormitory-life/auth/internal/constants"
+	"github.com/dormitory-life/auth/internal/database"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+func runUsers(subcommand string, args []string) error {
+	switch subcommand {
+	case "set-role":
+		return setUserRole(args)
+	default:
+		return fmt.Errorf("unknown users subcommand %q", subcommand)
+	}
+}
+
+// setUserRole changes a role directly in the database. It is meant for
+// bootstrapping the first admin of a dormitory; the last admin guard and the
+// role change log apply as for the API.
+func setUserRole(args []string) error {
+	flags := flag.NewFlagSet("users set-role", flag.ExitOnError)
+	configPath := flags.String("config", "configs/config.yaml", "path to the service config")
+	email := flags.String("email", "", "email of the user")
+	role := flags.String("role", "", "new role of the user")
+	flags.Parse(args)
+
+	if *email == "" || *role == "" {
+		return fmt.Errorf("-email and -role are required")
+	}
+
+	if !slices.Contains(constants.UserRoles, *role) {
+		return fmt.Errorf("unknown role %q, expected one of %v", *role, constants.UserRoles)
+	}
+
+	cfg, err := config.ParseConfig(*configPath)
+	if err != nil {
+		return err
+	}
+
+	db, err := database.InitDb(cfg.Db)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer db.Close()
+
+	repository := database.New(db)
+	ctx := context.Background()
+
+	user, err := repository.GetUserByEmail(ctx, &dbtypes.GetUserByEmailRequest{
+		Email: *email,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := repository.UpdateUserRole(ctx, &dbtypes.UpdateUserRoleRequest{
+		UserId:      user.UserId,
+		DormitoryId: user.DormitoryId,
+		Role:        *role,
+		ActorSource: dbtypes.RoleChangeSourceCLI,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("user %s (%s) of dormitory %s now has role %s\n",
+		resp.User.Email, resp.User.UserId, resp.User.DormitoryId, resp.User.Role)
+
+	return nil
+}
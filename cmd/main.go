@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"log/slog"
 	"os"
@@ -10,9 +11,15 @@ import (
 	"github.com/dormitory-life/auth/internal/config"
 	"github.com/dormitory-life/auth/internal/database"
 	"github.com/dormitory-life/auth/internal/grpc"
+	"github.com/dormitory-life/auth/internal/jwtkeys"
 	"github.com/dormitory-life/auth/internal/logger"
+	"github.com/dormitory-life/auth/internal/notifier"
+	"github.com/dormitory-life/auth/internal/outbox"
+	"github.com/dormitory-life/auth/internal/publisher"
 	"github.com/dormitory-life/auth/internal/server"
 	auth "github.com/dormitory-life/auth/internal/service"
+	"github.com/dormitory-life/auth/internal/sweeper"
+	"github.com/dormitory-life/auth/internal/webhook"
 
 	_ "github.com/dormitory-life/auth/docs"
 )
@@ -22,6 +29,12 @@ import (
 // @description API сервиса авторизации Dormitory Life
 // @BasePath /
 // @schemes http https
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access-токен в формате "Bearer <token>"
+// @securityDefinitions.basic ClientBasicAuth
+// @description Client id и секрет сервиса из server.introspection_clients
 
 func main() {
 	configPath := os.Args[1]
@@ -30,7 +43,8 @@ func main() {
 		panic(err)
 	}
 
-	log.Println("CONFIG: ", cfg)
+	// the config holds secrets, only where it came from is printed
+	log.Println("Auth config loaded from", configPath)
 
 	logger, err := logger.New(cfg)
 	if err != nil {
@@ -45,9 +59,42 @@ func main() {
 
 	repository := database.New(db)
 
+	keyring, err := jwtkeys.LoadKeyring(cfg.JWT)
+	if err != nil {
+		panic(err)
+	}
+
+	accountNotifier, err := notifier.New(cfg.Notifier, logger)
+	if err != nil {
+		panic(err)
+	}
+
+	eventPublisher, err := publisher.New(cfg.Outbox.Publisher, logger)
+	if err != nil {
+		panic(err)
+	}
+
+	webhookTargets, err := webhook.NewTargetPolicy(cfg.Webhooks.AllowedTargets)
+	if err != nil {
+		panic(err)
+	}
+
 	authService := auth.New(auth.AuthServiceConfig{
-		Repository: repository,
-		JWTSecret:  cfg.JWT.Secret,
+		Repository:      repository,
+		Logger:          logger,
+		Keyring:         keyring,
+		Issuer:          cfg.JWT.Issuer,
+		Audience:        cfg.JWT.Audience,
+		AccessTokenTTL:  cfg.JWT.AccessTokenTTL,
+		RefreshTokenTTL: cfg.JWT.RefreshTokenTTL,
+		Clients:         cfg.JWT.Clients,
+
+		Notifier:                 accountNotifier,
+		PasswordResetTTL:         cfg.Account.PasswordResetTTL,
+		EmailVerificationTTL:     cfg.Account.EmailVerificationTTL,
+		RequireEmailVerification: cfg.Account.RequireEmailVerification,
+
+		WebhookTargets: webhookTargets,
 	})
 
 	grpcServer := grpc.NewServer(grpc.GRPCServerConfig{
@@ -78,7 +125,63 @@ func main() {
 		panic(err)
 	}()
 
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+
+	grantSweeper := sweeper.New(sweeper.Config{
+		Repository: repository,
+		Logger:     logger,
+		Interval:   cfg.Account.GrantSweepInterval,
+	})
+
+	go grantSweeper.Run(workersCtx)
+
+	outboxRelay := outbox.New(outbox.Config{
+		Repository: repository,
+		Publisher:  publisher.NewMultiPublisher(eventPublisher, webhook.NewEnqueuer(repository)),
+		Logger:     logger,
+		Interval:   cfg.Outbox.RelayInterval,
+		BatchSize:  cfg.Outbox.BatchSize,
+	})
+
+	go outboxRelay.Run(workersCtx)
+
+	webhookDispatcher := webhook.NewDispatcher(webhook.Config{
+		Repository:  repository,
+		Logger:      logger,
+		Interval:    cfg.Webhooks.DispatchInterval,
+		BatchSize:   cfg.Webhooks.BatchSize,
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+		Timeout:     cfg.Webhooks.Timeout,
+		Targets:     webhookTargets,
+	})
+
+	go webhookDispatcher.Run(workersCtx)
+
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
-	<-sigChan
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range sigChan {
+		if sig != syscall.SIGHUP {
+			break
+		}
+
+		reloadKeyring(configPath, keyring, logger)
+	}
+}
+
+func reloadKeyring(configPath string, keyring *jwtkeys.Keyring, logger *slog.Logger) {
+	cfg, err := config.ParseConfig(configPath)
+	if err != nil {
+		logger.Error("keyring reload failed", slog.String("error", err.Error()))
+		return
+	}
+
+	reloaded, err := jwtkeys.LoadKeyring(cfg.JWT)
+	if err != nil {
+		logger.Error("keyring reload failed", slog.String("error", err.Error()))
+		return
+	}
+
+	keyring.Replace(reloaded)
+	logger.Info("keyring reloaded", slog.String("active_key", reloaded.Active().Id))
 }
@@ -15,45 +15,139 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
-            "post": {
-                "description": "Залогинивает пользователя и выдает токены",
-                "consumes": [
-                    "application/json"
-                ],
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Возвращает JWKS с публичными ключами, которыми другие сервисы могут проверять подпись токенов. При подписи общим секретом (HS256) список ключей пуст",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "auth"
                 ],
-                "summary": "Вход в систему",
-                "parameters": [
+                "summary": "Публичные ключи подписи токенов",
+                "responses": {
+                    "200": {
+                        "description": "Набор публичных ключей",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.JWKSResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/permissions": {
+            "get": {
+                "security": [
                     {
-                        "description": "Данные пользователя для входа",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает все разрешения сервиса. Разрешения с grantable=false выдаются только вместе с ролью",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Список разрешений",
+                "responses": {
+                    "200": {
+                        "description": "Разрешения",
                         "schema": {
-                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.LoginRequest"
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ListPermissionsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/admin/role-changes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает изменения ролей пользователей общежития администратора, новые первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Журнал смены ролей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Только изменения этого пользователя",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (не больше 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Пользователь авторизован",
+                        "description": "Изменения ролей",
                         "schema": {
-                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.LoginResponse"
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ListRoleChangesResponse"
                         }
                     },
                     "400": {
-                        "description": "Неверные данные / параметры запроса",
+                        "description": "Неверные параметры запроса",
                         "schema": {
                             "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Неверные данные для входа",
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
                         }
@@ -67,59 +161,178 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/ping": {
+        "/admin/users": {
             "get": {
-                "description": "Возвращает pong, если сервис авторизации работает",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает пользователей общежития администратора с пагинацией, фильтрами и сортировкой",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
+                ],
+                "summary": "Список пользователей общежития",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по роли",
+                        "name": "role",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Фильтр по статусу: active, suspended, banned, graduated",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Подстрока email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Созданы не раньше (RFC 3339)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Созданы раньше (RFC 3339)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "created_at",
+                        "description": "Поле сортировки: created_at, email, role",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "asc",
+                        "description": "Порядок сортировки: asc, desc",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (не больше 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Проверка доступности auth-сервиса",
                 "responses": {
                     "200": {
-                        "description": "pong",
+                        "description": "Пользователи",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ListUsersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/auth/refresh": {
-            "post": {
-                "description": "Выдает пользователю пару новых токенов",
-                "consumes": [
-                    "application/json"
+        "/admin/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Возвращает пользователя общежития администратора",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Обновление токенов",
+                "summary": "Пользователь общежития",
                 "parameters": [
                     {
-                        "description": "Пара старых токенов",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RefreshTokensRequest"
-                        }
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Новые токены выданы",
+                        "description": "Пользователь",
                         "schema": {
-                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RefreshTokensResponse"
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.AdminUser"
                         }
                     },
                     "400": {
-                        "description": "Неверные данные / параметры запроса",
+                        "description": "Неверный идентификатор пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
                         "schema": {
                             "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
                         }
@@ -131,11 +344,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Создает нового пользователя в системе",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет роль или статус пользователя общежития администратора. При смене статуса все сессии пользователя завершаются. Роль и статус меняются вместе: если одно изменение отклонено, второе не применяется",
                 "consumes": [
                     "application/json"
                 ],
@@ -143,25 +359,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Регистрация нового пользователя",
+                "summary": "Изменение пользователя",
                 "parameters": [
                     {
-                        "description": "Данные пользователя для регистрации",
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RegisterRequest"
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.AdminUpdateUserRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Пользователь зарегистрирован",
+                    "200": {
+                        "description": "Пользователь изменен",
                         "schema": {
-                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RegisterResponse"
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.AdminUser"
                         }
                     },
                     "400": {
@@ -170,8 +399,26 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав / пользователь имеет роль super_admin",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
                     "409": {
-                        "description": "Пользователь с такими данными уже существует",
+                        "description": "Нельзя понизить или заблокировать последнего администратора / действует временная роль",
                         "schema": {
                             "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
                         }
@@ -184,28 +431,2247 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "details": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
+        },
+        "/admin/users/{id}/dormitories": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает общежития, в которых пользователь может действовать помимо своего. Доступно только super_admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Общежития пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Общежития пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.DormitoryScopesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный идентификатор пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
                     }
-                },
-                "error": {
-                    "type": "string"
                 }
             }
         },
-        "github_com_dormitory-life_auth_internal_server_request_models.LoginRequest": {
-            "type": "object",
-            "properties": {
-                "email": {
-                    "type": "string"
+        "/admin/users/{id}/dormitories/{dormitory_id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Разрешает пользователю действовать в общежитии со своей ролью. Повторное добавление не является ошибкой. Доступно только super_admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Добавление общежития пользователю",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор общежития",
+                        "name": "dormitory_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Общежития пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.DormitoryScopesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный идентификатор пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь или общежитие не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Запрещает пользователю действовать в общежитии. Собственное общежитие пользователя не затрагивается. Доступно только super_admin",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Удаление общежития у пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор общежития",
+                        "name": "dormitory_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Общежитие удалено"
+                    },
+                    "400": {
+                        "description": "Неверный идентификатор пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Общежитие не было добавлено",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает разрешения роли пользователя общежития администратора и выданные ему отдельно",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Разрешения пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Разрешения пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.UserPermissionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный идентификатор пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/permissions/{permission}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выдает разрешение пользователю общежития администратора в дополнение к разрешениям его роли. Тело запроса необязательно: в нем можно ограничить срок действия разрешения. Повторная выдача заменяет срок действия",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Выдача разрешения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название разрешения, например news.publish",
+                        "name": "permission",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Срок действия разрешения",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.GrantPermissionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Разрешения пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.UserPermissionsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный срок действия / разрешение выдается только вместе с ролью",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь или разрешение не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отзывает выданное пользователю разрешение. Разрешения роли пользователя не затрагиваются",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Отзыв разрешения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Название разрешения",
+                        "name": "permission",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Разрешение отозвано"
+                    },
+                    "400": {
+                        "description": "Неверный идентификатор пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Разрешение не было выдано",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Назначает или снимает роль пользователя общежития администратора. Последнего активного администратора общежития понизить нельзя, как и сменить роль, пока действует временная роль пользователя. Администраторы только по временной роли не считаются. Каждое изменение записывается в журнал",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Смена роли пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новая роль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ChangeUserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Роль изменена",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.AdminUser"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Нельзя понизить последнего администратора / действует временная роль",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/role-grants": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает временные роли пользователя общежития администратора, новые первыми, включая завершенные",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Временные роли пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Временные роли",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ListRoleGrantsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверный идентификатор пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выдает пользователю общежития администратора роль на срок. Пока срок действует, роль заменяет роль пользователя; по окончании срока сессии пользователя отзываются. Начало и конец срока записываются в журнал смены ролей. Последнему администратору общежития нельзя выдать другую роль, пользователю с ролью super_admin роль на срок не выдается",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Временная роль",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Роль и срок ее действия",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.CreateRoleGrantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Роль выдана",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RoleGrant"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав / пользователь имеет роль super_admin",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Нельзя понизить последнего администратора",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}/role-grants/{grant_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Завершает временную роль досрочно или отменяет ее, если срок еще не начался. Сессии пользователя отзываются при следующей проверке сроков",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Завершение временной роли",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор пользователя",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор временной роли",
+                        "name": "grant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Временная роль завершена"
+                    },
+                    "400": {
+                        "description": "Неверный идентификатор пользователя или временной роли",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Временная роль не найдена или уже завершена",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает вебхуки общежития администратора без секретов",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Вебхуки общежития",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вебхуки",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ListWebhooksResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Подписывает URL на события пользователей общежития администратора: user.registered, user.logged_in, user.role_changed. Каждая доставка подписывается HMAC-SHA256 от \"\u003cX-Webhook-Timestamp\u003e.\u003cтело\u003e\" с секретом вебхука, подпись передается в заголовке X-Webhook-Signature как \"sha256=\u003chex\u003e\". Секрет хранится в открытом виде, так как нужен для подписи, и возвращается только при создании и смене. Адреса внутренней сети (loopback, частные, link-local) запрещены, если они не разрешены в конфигурации",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Создание вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "description": "URL и типы событий",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Вебхук создан",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет вебхук общежития администратора вместе с журналом его доставок",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Удаление вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Вебхук удален"
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет URL, типы событий или приостанавливает вебхук общежития администратора. События для приостановленного вебхука не ставятся в очередь",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Изменение вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменяемые поля",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Вебхук изменен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает доставки событий вебхука, новые первыми. Доставка ожидает (pending), пока событие не доставлено (delivered) или не исчерпаны попытки (dead)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Доставки вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "pending",
+                            "delivered",
+                            "dead"
+                        ],
+                        "type": "string",
+                        "description": "Только доставки в этом статусе",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (не больше 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Смещение",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Доставки",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ListWebhookDeliveriesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}/deliveries/{delivery_id}/attempts": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает журнал попыток доставки события вебхука, старые первыми",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Попытки доставки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор доставки",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Попытки доставки",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ListWebhookDeliveryAttemptsResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Доставка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}/deliveries/{delivery_id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает доставку, исчерпавшую попытки (dead), в очередь с новым набором попыток",
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Повтор доставки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор доставки",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Доставка поставлена в очередь"
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Доставка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Доставка не в статусе dead",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/webhooks/{id}/rotate-secret": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет секрет вебхука общежития администратора новым и возвращает его. Еще не отправленные доставки, в том числе повторы, подписываются новым секретом",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Смена секрета вебхука",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Общежитие, по умолчанию общежитие администратора",
+                        "name": "dormitory_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор вебхука",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Секрет заменен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.Webhook"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Вебхук не найден",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/email/resend": {
+            "post": {
+                "description": "Отправляет новый токен подтверждения email, предыдущие токены перестают действовать. Ответ не зависит от того, зарегистрирован ли email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Повторная отправка письма с подтверждением",
+                "parameters": [
+                    {
+                        "description": "Email пользователя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ResendVerificationEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Запрос принят"
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/email/verify": {
+            "post": {
+                "description": "Подтверждает email пользователя по одноразовому токену из письма",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Подтверждение email",
+                "parameters": [
+                    {
+                        "description": "Токен подтверждения",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Email подтвержден"
+                    },
+                    "400": {
+                        "description": "Неверные данные, токен недействителен или истек",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/introspect": {
+            "post": {
+                "security": [
+                    {
+                        "ClientBasicAuth": []
+                    }
+                ],
+                "description": "Проверяет подпись, срок действия и отзыв access-токена (RFC 7662). Для недействительного токена возвращает active=false. Вызывающий сервис передает client id и секрет из server.introspection_clients через HTTP Basic",
+                "consumes": [
+                    "application/json",
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Интроспекция токена",
+                "parameters": [
+                    {
+                        "description": "Проверяемый токен",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.IntrospectRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Результат проверки токена",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.IntrospectResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверные учетные данные сервиса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Залогинивает пользователя и выдает токены",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Вход в систему",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя для входа",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пользователь авторизован",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверные данные для входа",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Email не подтвержден или аккаунт заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "description": "Отзывает refresh-токен и завершает текущую сессию пользователя",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выход из системы",
+                "parameters": [
+                    {
+                        "description": "Refresh-токен текущей сессии",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Сессия завершена"
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Refresh-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout-all": {
+            "post": {
+                "description": "Отзывает все refresh-токены пользователя, которому принадлежит переданный токен",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выход со всех устройств",
+                "parameters": [
+                    {
+                        "description": "Refresh-токен текущей сессии",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.LogoutRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Все сессии пользователя завершены"
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Refresh-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/change": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет пароль авторизованного пользователя после проверки текущего пароля. При revoke_other_sessions=true завершает все сессии, кроме текущей",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Смена пароля",
+                "parameters": [
+                    {
+                        "description": "Текущий и новый пароль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Пароль изменен"
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Access-токен недействителен",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Неверный текущий пароль",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/forgot": {
+            "post": {
+                "description": "Создает одноразовый токен сброса пароля и отправляет его пользователю. Ответ не зависит от того, зарегистрирован ли email",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Запрос на сброс пароля",
+                "parameters": [
+                    {
+                        "description": "Email пользователя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ForgotPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Запрос принят"
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/password/reset": {
+            "post": {
+                "description": "Устанавливает новый пароль по токену сброса и завершает все сессии пользователя. Токен можно использовать только один раз",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Сброс пароля",
+                "parameters": [
+                    {
+                        "description": "Токен сброса и новый пароль",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Пароль изменен"
+                    },
+                    "400": {
+                        "description": "Неверные данные, токен недействителен или истек",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/ping": {
+            "get": {
+                "description": "Возвращает pong, если сервис авторизации работает",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Проверка доступности auth-сервиса",
+                "responses": {
+                    "200": {
+                        "description": "pong",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Обменивает refresh-токен на пару новых токенов. Использованный refresh-токен становится недействительным, повторное его предъявление отзывает всю сессию",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Обновление токенов",
+                "parameters": [
+                    {
+                        "description": "Пара старых токенов",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RefreshTokensRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новые токены выданы",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RefreshTokensResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Refresh-токен недействителен, истек или отозван",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Аккаунт заблокирован",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Создает нового пользователя в системе и отправляет письмо для подтверждения email. Если подтверждение обязательно, токены не выдаются",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Регистрация нового пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные пользователя для регистрации",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Пользователь зарегистрирован",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Неверные данные / параметры запроса",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь с такими данными уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_dormitory-life_auth_internal_server_request_models.AdminUpdateUserRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status changes revoke all sessions of the user. StatusReason and\nStatusExpiresAt are stored with the new status; after StatusExpiresAt\nthe user is active again.",
+                    "type": "string"
+                },
+                "status_expires_at": {
+                    "type": "string"
+                },
+                "status_reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.AdminUser": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "dormitory_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified_at": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "status_expires_at": {
+                    "type": "string"
+                },
+                "status_reason": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ChangePasswordRequest": {
+            "type": "object",
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                },
+                "revoke_other_sessions": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ChangeUserRoleRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.CreateRoleGrantRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "description": "ValidFrom defaults to now.",
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.CreateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "description": "EventTypes limits the events sent to the webhook. Empty sends every\nevent.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.DormitoryScope": {
+            "type": "object",
+            "properties": {
+                "dormitory_id": {
+                    "type": "string"
+                },
+                "granted_at": {
+                    "type": "string"
+                },
+                "granted_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.DormitoryScopesResponse": {
+            "type": "object",
+            "properties": {
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.DormitoryScope"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ForgotPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.GrantPermissionRequest": {
+            "type": "object",
+            "properties": {
+                "valid_from": {
+                    "description": "ValidFrom and ValidUntil limit the grant in time. Without them it\napplies at once and does not expire.",
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.IntrospectRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "token_type_hint": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.IntrospectResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "aud": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "client_id": {
+                    "type": "string"
+                },
+                "dormitories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dormitory_id": {
+                    "type": "string"
+                },
+                "exp": {
+                    "type": "integer"
+                },
+                "iat": {
+                    "type": "integer"
+                },
+                "iss": {
+                    "type": "string"
+                },
+                "jti": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "sid": {
+                    "type": "string"
+                },
+                "sub": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                },
+                "y": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.JWKSResponse": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.JWK"
+                    }
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ListPermissionsResponse": {
+            "type": "object",
+            "properties": {
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.Permission"
+                    }
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ListRoleChangesResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RoleChange"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ListRoleGrantsResponse": {
+            "type": "object",
+            "properties": {
+                "grants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.RoleGrant"
+                    }
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ListUsersResponse": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.AdminUser"
+                    }
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ListWebhookDeliveriesResponse": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.WebhookDelivery"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ListWebhookDeliveryAttemptsResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.WebhookDeliveryAttempt"
+                    }
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ListWebhooksResponse": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.Webhook"
+                    }
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "client_id": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
                 },
                 "password": {
                     "type": "string"
@@ -229,19 +2695,55 @@ const docTemplate = `{
                 }
             }
         },
-        "github_com_dormitory-life_auth_internal_server_request_models.RefreshTokensRequest": {
+        "github_com_dormitory-life_auth_internal_server_request_models.LogoutRequest": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.Permission": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "grantable": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.PermissionGrant": {
+            "type": "object",
+            "properties": {
+                "granted_at": {
                     "type": "string"
                 },
-                "dormitoryId": {
+                "granted_by": {
                     "type": "string"
                 },
-                "refresh_token": {
+                "permission": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.RefreshTokensRequest": {
+            "type": "object",
+            "properties": {
+                "access_token": {
                     "type": "string"
                 },
-                "userId": {
+                "refresh_token": {
                     "type": "string"
                 }
             }
@@ -260,6 +2762,9 @@ const docTemplate = `{
         "github_com_dormitory-life_auth_internal_server_request_models.RegisterRequest": {
             "type": "object",
             "properties": {
+                "client_id": {
+                    "type": "string"
+                },
                 "dormitory_id": {
                     "type": "string"
                 },
@@ -280,6 +2785,10 @@ const docTemplate = `{
                 "dormitory_id": {
                     "type": "string"
                 },
+                "email_verification_required": {
+                    "description": "EmailVerificationRequired is set when tokens are not issued until\nthe email is verified.",
+                    "type": "boolean"
+                },
                 "refresh_token": {
                     "type": "string"
                 },
@@ -287,6 +2796,238 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ResendVerificationEmailRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.ResetPasswordRequest": {
+            "type": "object",
+            "properties": {
+                "new_password": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.RoleChange": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_source": {
+                    "type": "string"
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "new_role": {
+                    "type": "string"
+                },
+                "old_role": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.RoleGrant": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expired_at": {
+                    "type": "string"
+                },
+                "granted_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "valid_from": {
+                    "type": "string"
+                },
+                "valid_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.UpdateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "Active false pauses the webhook. Events of a paused webhook are not\nqueued.",
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "description": "EventTypes replaces the event types when set; an empty list sends\nevery event.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.UserPermissionsResponse": {
+            "type": "object",
+            "properties": {
+                "grants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_dormitory-life_auth_internal_server_request_models.PermissionGrant"
+                    }
+                },
+                "role": {
+                    "type": "string"
+                },
+                "role_permissions": {
+                    "description": "RolePermissions come with the role of the user, Grants were given\nto the user on top of it.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.VerifyEmailRequest": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.Webhook": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "dormitory_id": {
+                    "type": "string"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "secret": {
+                    "description": "Secret signs the deliveries. It is only shown when the webhook is\ncreated and when the secret is rotated.",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "object"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "description": "NextAttemptAt is set while the delivery is pending.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_dormitory-life_auth_internal_server_request_models.WebhookDeliveryAttempt": {
+            "type": "object",
+            "properties": {
+                "attempted_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status_code": {
+                    "description": "StatusCode is missing when no answer came back.",
+                    "type": "integer"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "BearerAuth": {
+            "description": "Access-токен в формате \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
+        "ClientBasicAuth": {
+            "type": "basic"
         }
     }
 }`
@@ -298,7 +3039,7 @@ var SwaggerInfo = &swag.Spec{
 	BasePath:         "/",
 	Schemes:          []string{"http", "https"},
 	Title:            "Dormitory Life Auth API",
-	Description:      "API сервиса авторизации Dormitory Life",
+	Description:      "Client id и секрет сервиса из server.introspection_clients",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
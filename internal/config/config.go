@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
@@ -15,6 +16,10 @@ type Config struct {
 	Server     ServerConfig     `yaml:"server"`
 	JWT        JWTConfig        `yaml:"jwt"`
 	GRPCServer GRPCServerConfig `yaml:"grpc_server"`
+	Account    AccountConfig    `yaml:"account"`
+	Notifier   NotifierConfig   `yaml:"notifier"`
+	Outbox     OutboxConfig     `yaml:"outbox"`
+	Webhooks   WebhooksConfig   `yaml:"webhooks"`
 }
 
 type DataBaseConfig struct {
@@ -30,16 +35,105 @@ type DataBaseConfig struct {
 
 type ServerConfig struct {
 	Port uint16 `yaml:"port"`
+	// IntrospectionClients maps the ids of the services allowed to call the
+	// introspection endpoint to their secrets.
+	IntrospectionClients map[string]string `yaml:"introspection_clients,omitempty"`
 }
 
 type JWTConfig struct {
-	Secret string `yaml:"secret"`
+	Secret         string         `yaml:"secret,omitempty"`
+	Algorithm      string         `yaml:"algorithm,omitempty"`
+	KeyId          string         `yaml:"key_id,omitempty"`
+	PrivateKeyPath string         `yaml:"private_key_path,omitempty"`
+	Keys           []JWTKeyConfig `yaml:"keys,omitempty"`
+	Issuer         string         `yaml:"issuer,omitempty"`
+	Audience       []string       `yaml:"audience,omitempty"`
+
+	AccessTokenTTL  time.Duration                  `yaml:"access_token_ttl,omitempty"`
+	RefreshTokenTTL time.Duration                  `yaml:"refresh_token_ttl,omitempty"`
+	Clients         map[string]TokenLifetimeConfig `yaml:"clients,omitempty"`
+}
+
+type TokenLifetimeConfig struct {
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl,omitempty"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl,omitempty"`
+}
+
+type JWTKeyConfig struct {
+	Id             string    `yaml:"id"`
+	Algorithm      string    `yaml:"algorithm"`
+	Secret         string    `yaml:"secret,omitempty"`
+	PrivateKeyPath string    `yaml:"private_key_path,omitempty"`
+	PublicKeyPath  string    `yaml:"public_key_path,omitempty"`
+	Active         bool      `yaml:"active"`
+	NotAfter       time.Time `yaml:"not_after,omitempty"`
 }
 
 type GRPCServerConfig struct {
 	Port string `yaml:"port"`
 }
 
+type AccountConfig struct {
+	PasswordResetTTL     time.Duration `yaml:"password_reset_ttl"`
+	EmailVerificationTTL time.Duration `yaml:"email_verification_ttl"`
+	// RequireEmailVerification makes Login refuse accounts whose email has
+	// not been verified yet.
+	RequireEmailVerification bool `yaml:"require_email_verification"`
+	// GrantSweepInterval is how often time-bounded grants are started and
+	// expired.
+	GrantSweepInterval time.Duration `yaml:"grant_sweep_interval"`
+}
+
+type NotifierConfig struct {
+	Type     string     `yaml:"type"`
+	FilePath string     `yaml:"file_path"`
+	SMTP     SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     uint16 `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+	// ResetPasswordURL and VerifyEmailURL are frontend pages that receive
+	// the token in the "token" query parameter.
+	ResetPasswordURL string `yaml:"reset_password_url"`
+	VerifyEmailURL   string `yaml:"verify_email_url"`
+}
+
+type OutboxConfig struct {
+	// RelayInterval is how often the relay looks for events to publish.
+	RelayInterval time.Duration   `yaml:"relay_interval"`
+	BatchSize     uint64          `yaml:"batch_size"`
+	Publisher     PublisherConfig `yaml:"publisher"`
+}
+
+type PublisherConfig struct {
+	Type     string        `yaml:"type"`
+	FilePath string        `yaml:"file_path"`
+	Webhook  WebhookConfig `yaml:"webhook"`
+}
+
+type WebhookConfig struct {
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// WebhooksConfig tunes the delivery of events to the webhooks of dormitory
+// admins.
+type WebhooksConfig struct {
+	DispatchInterval time.Duration `yaml:"dispatch_interval"`
+	BatchSize        uint64        `yaml:"batch_size"`
+	// MaxAttempts is the number of attempts after which a delivery is dead.
+	MaxAttempts int           `yaml:"max_attempts"`
+	Timeout     time.Duration `yaml:"timeout"`
+	// AllowedTargets lists host names, IP addresses and CIDR ranges that
+	// webhooks may reach although they are internal. Everything else on
+	// loopback, private or link-local addresses is refused.
+	AllowedTargets []string `yaml:"allowed_targets,omitempty"`
+}
+
 func ParseConfig(path string) (*Config, error) {
 	config := &Config{}
 
@@ -59,6 +153,51 @@ func ParseConfig(path string) (*Config, error) {
 	return config, nil
 }
 
+// UpdateJWTConfig rewrites the jwt section of the config file at path,
+// leaving the other sections untouched.
+func UpdateJWTConfig(path string, update func(cfg *JWTConfig) error) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var document yaml.MapSlice
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("failed to decode config file: %w", err)
+	}
+
+	for i, item := range document {
+		if item.Key != "jwt" {
+			continue
+		}
+
+		section, err := yaml.Marshal(item.Value)
+		if err != nil {
+			return fmt.Errorf("failed to encode jwt section: %w", err)
+		}
+
+		var jwtConfig JWTConfig
+		if err := yaml.Unmarshal(section, &jwtConfig); err != nil {
+			return fmt.Errorf("failed to decode jwt section: %w", err)
+		}
+
+		if err := update(&jwtConfig); err != nil {
+			return err
+		}
+
+		document[i].Value = jwtConfig
+
+		data, err = yaml.Marshal(document)
+		if err != nil {
+			return fmt.Errorf("failed to encode config file: %w", err)
+		}
+
+		return os.WriteFile(path, data, 0o644)
+	}
+
+	return fmt.Errorf("jwt section not found in %s", path)
+}
+
 func (c *DataBaseConfig) GetConnectionString() string {
 	return fmt.Sprintf(
 		dbConnectionStringTemplate,
@@ -5,6 +5,7 @@ import "errors"
 var (
 	ErrBadRequest          = errors.New("bad request")
 	ErrConflict            = errors.New("conflict")
+	ErrForbidden           = errors.New("forbidden")
 	ErrInternalServerError = errors.New("internal server error")
 	ErrNotFound            = errors.New("not found")
 	ErrUnauthorized        = errors.New("unauthorized")
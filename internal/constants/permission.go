@@ -0,0 +1,22 @@
+package constants
+
+// Permissions checked by the service itself. The full catalogue and the
+// permissions of every role live in the permissions and role_permissions
+// tables; super_admin holds every permission without rows there.
+const (
+	// PermissionDormitoryAccess is what CheckAccess asks for when no role is
+	// required: any resident or staff member of the dormitory.
+	PermissionDormitoryAccess = "dormitory.access"
+	// PermissionDormitoryAdmin is what CheckAccess asks for when a role is
+	// required. Only admins have it.
+	PermissionDormitoryAdmin = "dormitory.admin"
+)
+
+// Reasons an access check is denied. Callers should branch on these rather
+// than on the human readable reason.
+const (
+	DenialReasonUserNotFound         = "user_not_found"
+	DenialReasonOtherDormitory       = "other_dormitory"
+	DenialReasonAccountInactive      = "account_inactive"
+	DenialReasonPermissionNotGranted = "permission_not_granted"
+)
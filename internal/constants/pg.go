@@ -5,5 +5,24 @@ const (
 )
 
 const (
-	UsersTableName string = "users"
+	UsersTableName               string = "users"
+	RefreshTokensTableName       string = "refresh_tokens"
+	PasswordResetTokensTableName string = "password_reset_tokens"
+
+	EmailVerificationTokensTableName string = "email_verification_tokens"
+	RoleChangesTableName             string = "role_changes"
+
+	PermissionsTableName     string = "permissions"
+	RolePermissionsTableName string = "role_permissions"
+	UserPermissionsTableName string = "user_permissions"
+
+	UserDormitoryScopesTableName string = "user_dormitory_scopes"
+	RoleGrantsTableName          string = "role_grants"
+
+	UserChangesTableName  string = "user_changes"
+	OutboxEventsTableName string = "outbox_events"
+
+	WebhookSubscriptionsTableName    string = "webhook_subscriptions"
+	WebhookDeliveriesTableName       string = "webhook_deliveries"
+	WebhookDeliveryAttemptsTableName string = "webhook_delivery_attempts"
 )
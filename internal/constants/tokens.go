@@ -7,4 +7,7 @@ const (
 	AccessTokenTTL    time.Duration = 15 * time.Minute
 	RefreshPrivateKey string        = "refresh"
 	RefreshTokenTTL   time.Duration = 7 * 24 * time.Hour
+
+	PasswordResetTokenTTL     time.Duration = time.Hour
+	EmailVerificationTokenTTL time.Duration = 24 * time.Hour
 )
@@ -1,6 +1,30 @@
 package constants
 
 const (
-	UserStudentRole = "student"
-	UserAdminRole   = "admin"
+	UserStudentRole       = "student"
+	UserAdminRole         = "admin"
+	UserFloorWardenRole   = "floor_warden"
+	UserSecurityGuardRole = "security_guard"
+	UserMaintenanceRole   = "maintenance"
+
+	// UserSuperAdminRole acts in every dormitory. It can only be given with
+	// authctl.
+	UserSuperAdminRole = "super_admin"
+)
+
+// UserRoles lists every role a user can be given.
+var UserRoles = []string{
+	UserStudentRole,
+	UserAdminRole,
+	UserFloorWardenRole,
+	UserSecurityGuardRole,
+	UserMaintenanceRole,
+	UserSuperAdminRole,
+}
+
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusBanned    = "banned"
+	UserStatusGraduated = "graduated"
 )
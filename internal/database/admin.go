@@ -0,0 +1,333 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+// adminUserColumns are the columns of users shown to admins, in the order
+// scanAdminUser reads them.
+var adminUserColumns = []string{
+	"id", "email", "dormitory_id", "role", "created_at", "status",
+	"email_verified_at", "status_reason", "status_expires_at",
+}
+
+var userSortColumns = map[string]string{
+	dbtypes.UserSortByCreatedAt: "created_at",
+	dbtypes.UserSortByEmail:     "email",
+	dbtypes.UserSortByRole:      "role",
+}
+
+func (c *Database) ListUsers(
+	ctx context.Context,
+	request *dbtypes.ListUsersRequest,
+) (*dbtypes.ListUsersResponse, error) {
+	if request == nil || request.DormitoryId == "" {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	resp, err := c.listUsers(ctx, c.db, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) listUsers(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.ListUsersRequest,
+) (*dbtypes.ListUsersResponse, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	sortColumn, ok := userSortColumns[request.SortBy]
+	if !ok {
+		if request.SortBy != "" {
+			return nil, fmt.Errorf("%w: unknown sort column %q", dberrors.ErrBadRequest, request.SortBy)
+		}
+
+		sortColumn = userSortColumns[dbtypes.UserSortByCreatedAt]
+	}
+
+	sortOrder := "ASC"
+	if request.SortDesc {
+		sortOrder = "DESC"
+	}
+
+	now := time.Now().UTC()
+
+	filter := listUsersFilter(request, now)
+
+	countQuery, countArgs, err := psql.
+		Select("COUNT(*)").
+		From(usersTable).
+		Where(filter).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building count users query: %v", dberrors.ErrInternal, err)
+	}
+
+	var total uint64
+	err = driver.QueryRowContext(ctx, countQuery, countArgs...).Scan(&total)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing count users query: %v", dberrors.ErrInternal, err)
+	}
+
+	// the role shown and sorted on is the effective one, as in AdminGetUser
+	var sortBy squirrel.Sqlizer = squirrel.Expr(sortColumn)
+	if request.SortBy == dbtypes.UserSortByRole {
+		sortBy = effectiveRole(now)
+	}
+
+	queryBuilder := psql.
+		Select("id", "email", "dormitory_id").
+		Column(effectiveRoleColumn(now)).
+		Columns("created_at", "status", "email_verified_at", "status_reason", "status_expires_at").
+		From(usersTable).
+		Where(filter).
+		OrderByClause(squirrel.Expr("? "+sortOrder, sortBy)).
+		OrderBy("id ASC").
+		Limit(request.Limit).
+		Offset(request.Offset)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building list users query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing list users query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	users := make([]dbtypes.User, 0, request.Limit)
+	for rows.Next() {
+		user, err := scanAdminUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning user: %v", dberrors.ErrInternal, err)
+		}
+
+		users = append(users, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating users: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.ListUsersResponse{
+		Users: users,
+		Total: total,
+	}, nil
+}
+
+func listUsersFilter(request *dbtypes.ListUsersRequest, now time.Time) squirrel.And {
+	filter := squirrel.And{
+		squirrel.Eq{"dormitory_id": request.DormitoryId},
+	}
+
+	if request.Role != "" {
+		filter = append(filter, squirrel.Expr("? = ?", effectiveRole(now), request.Role))
+	}
+
+	if request.Status != "" {
+		filter = append(filter, squirrel.Expr("? = ?", effectiveStatusExpr(now), request.Status))
+	}
+
+	if request.EmailContains != "" {
+		filter = append(filter, squirrel.ILike{"email": "%" + escapeLike(request.EmailContains) + "%"})
+	}
+
+	if request.CreatedFrom != nil {
+		filter = append(filter, squirrel.GtOrEq{"created_at": *request.CreatedFrom})
+	}
+
+	if request.CreatedTo != nil {
+		filter = append(filter, squirrel.Lt{"created_at": *request.CreatedTo})
+	}
+
+	return filter
+}
+
+// effectiveStatusExpr is the status a user has at now: a status whose expiry
+// has passed no longer applies and the user is active again.
+func effectiveStatusExpr(now time.Time) squirrel.Sqlizer {
+	return squirrel.Expr(
+		"(CASE WHEN status <> ? AND status_expires_at <= ? THEN ? ELSE status END)",
+		constants.UserStatusActive, now, constants.UserStatusActive,
+	)
+}
+
+// escapeLike makes the wildcards of a LIKE pattern match literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAdminUser(row rowScanner) (*dbtypes.User, error) {
+	var (
+		user      dbtypes.User
+		nullables nullableUserColumns
+	)
+
+	err := row.Scan(append([]any{
+		&user.UserId,
+		&user.Email,
+		&user.DormitoryId,
+		&user.Role,
+		&user.CreatedAt,
+		&user.Status,
+	}, nullables.dest()...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	nullables.apply(&user)
+
+	return &user, nil
+}
+
+// changeUserStatus sets the status of a user inside the transaction of the
+// caller, records the change and revokes the sessions of the user.
+func (c *Database) changeUserStatus(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.UpdateUserStatusRequest,
+) (*dbtypes.UpdateUserStatusResponse, error) {
+	admins, err := c.lockDormitoryAdmins(ctx, driver, request.DormitoryId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if admins.role == constants.UserSuperAdminRole {
+		return nil, fmt.Errorf("%w: the status of a super admin cannot be changed by a dormitory admin", dberrors.ErrForbidden)
+	}
+
+	if admins.role == constants.UserAdminRole && request.Status != constants.UserStatusActive && admins.otherAdmins == 0 {
+		return nil, fmt.Errorf("%w: cannot block the last admin of the dormitory", dberrors.ErrConflict)
+	}
+
+	resp, err := c.updateUserStatus(ctx, driver, request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.createUserChange(ctx, driver, &dbtypes.UserChange{
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+		Kind:        dbtypes.UserChangeKindStatus,
+		NewValue:    &request.Status,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.revokeRefreshTokens(ctx, driver, squirrel.Eq{"user_id": request.UserId}); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// UpdateUser changes the role and the status of a user in one transaction,
+// so when one change is refused the other is not applied either. Either may
+// be missing.
+func (c *Database) UpdateUser(
+	ctx context.Context,
+	request *dbtypes.UpdateUserRequest,
+) (*dbtypes.UpdateUserResponse, error) {
+	if request == nil || (request.Role == nil && request.Status == nil) {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	resp := &dbtypes.UpdateUserResponse{}
+
+	err := c.withTx(ctx, func(tx Driver) error {
+		if request.Role != nil {
+			roleResp, err := c.changeUserRole(ctx, tx, request.Role)
+			if err != nil {
+				return err
+			}
+
+			resp.User = roleResp.User
+		}
+
+		if request.Status != nil {
+			statusResp, err := c.changeUserStatus(ctx, tx, request.Status)
+			if err != nil {
+				return err
+			}
+
+			resp.User = statusResp.User
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) updateUserStatus(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.UpdateUserStatusRequest,
+) (*dbtypes.UpdateUserStatusResponse, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	statusReason := sql.NullString{
+		String: request.Reason,
+		Valid:  request.Reason != "",
+	}
+
+	queryBuilder := psql.Update(usersTable).
+		Set("status", request.Status).
+		Set("status_reason", statusReason).
+		Set("status_expires_at", request.ExpiresAt).
+		Set("status_changed_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{
+			"id":           request.UserId,
+			"dormitory_id": request.DormitoryId,
+		}).
+		Suffix("RETURNING " + strings.Join(adminUserColumns, ", "))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building update user status query: %v", dberrors.ErrInternal, err)
+	}
+
+	user, err := scanAdminUser(driver.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error executing update user status query: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.UpdateUserStatusResponse{
+		User: user,
+	}, nil
+}
@@ -21,7 +21,19 @@ func (c *Database) Register(
 		return nil, dberrors.ErrBadRequest
 	}
 
-	resp, err := c.register(ctx, c.db, request)
+	var resp *dbtypes.RegisterResponse
+
+	err := c.withTx(ctx, func(tx Driver) error {
+		var err error
+		resp, err = c.register(ctx, tx, request)
+		if err != nil {
+			return err
+		}
+
+		return c.createOutboxEvent(ctx, tx, resp.UserId, resp.DormitoryId, dbtypes.UserRegisteredPayload{
+			Email: request.Email,
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
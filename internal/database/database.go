@@ -3,11 +3,13 @@ package database
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"log"
 
 	_ "github.com/lib/pq"
 
 	"github.com/dormitory-life/auth/internal/config"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
 	dbtypes "github.com/dormitory-life/auth/internal/database/types"
 	"github.com/dormitory-life/utils/migrator"
 )
@@ -39,6 +41,60 @@ type Repository interface {
 
 	GetUserByEmail(ctx context.Context, request *dbtypes.GetUserByEmailRequest) (*dbtypes.GetUserResponse, error)
 	GetUserById(ctx context.Context, request *dbtypes.GetUserInfoByIdRequest) (*dbtypes.GetUserInfoByIdResponse, error)
+	GetUsersByIds(ctx context.Context, request *dbtypes.GetUsersByIdsRequest) (*dbtypes.GetUsersByIdsResponse, error)
+	GetUserPassword(ctx context.Context, request *dbtypes.GetUserPasswordRequest) (*dbtypes.GetUserPasswordResponse, error)
+	UpdatePassword(ctx context.Context, request *dbtypes.UpdatePasswordRequest) error
+
+	ListUsers(ctx context.Context, request *dbtypes.ListUsersRequest) (*dbtypes.ListUsersResponse, error)
+	UpdateUserRole(ctx context.Context, request *dbtypes.UpdateUserRoleRequest) (*dbtypes.UpdateUserRoleResponse, error)
+	UpdateUser(ctx context.Context, request *dbtypes.UpdateUserRequest) (*dbtypes.UpdateUserResponse, error)
+	ListRoleChanges(ctx context.Context, request *dbtypes.ListRoleChangesRequest) (*dbtypes.ListRoleChangesResponse, error)
+
+	CreateRoleGrant(ctx context.Context, request *dbtypes.CreateRoleGrantRequest) (*dbtypes.CreateRoleGrantResponse, error)
+	ListRoleGrants(ctx context.Context, request *dbtypes.ListRoleGrantsRequest) (*dbtypes.ListRoleGrantsResponse, error)
+	EndRoleGrant(ctx context.Context, request *dbtypes.EndRoleGrantRequest) error
+	SweepGrants(ctx context.Context, request *dbtypes.SweepGrantsRequest) (*dbtypes.SweepGrantsResponse, error)
+
+	ListUserChanges(ctx context.Context, request *dbtypes.ListUserChangesRequest) (*dbtypes.ListUserChangesResponse, error)
+	GetLastUserChangeId(ctx context.Context) (*dbtypes.GetLastUserChangeIdResponse, error)
+
+	ClaimOutboxEvents(ctx context.Context, request *dbtypes.ClaimOutboxEventsRequest) (*dbtypes.ClaimOutboxEventsResponse, error)
+	MarkOutboxEventPublished(ctx context.Context, request *dbtypes.MarkOutboxEventPublishedRequest) error
+	MarkOutboxEventFailed(ctx context.Context, request *dbtypes.MarkOutboxEventFailedRequest) error
+
+	CreateWebhookSubscription(ctx context.Context, request *dbtypes.CreateWebhookSubscriptionRequest) (*dbtypes.CreateWebhookSubscriptionResponse, error)
+	ListWebhookSubscriptions(ctx context.Context, request *dbtypes.ListWebhookSubscriptionsRequest) (*dbtypes.ListWebhookSubscriptionsResponse, error)
+	UpdateWebhookSubscription(ctx context.Context, request *dbtypes.UpdateWebhookSubscriptionRequest) (*dbtypes.UpdateWebhookSubscriptionResponse, error)
+	DeleteWebhookSubscription(ctx context.Context, request *dbtypes.DeleteWebhookSubscriptionRequest) error
+	EnqueueWebhookDeliveries(ctx context.Context, request *dbtypes.EnqueueWebhookDeliveriesRequest) error
+	ClaimWebhookDeliveries(ctx context.Context, request *dbtypes.ClaimWebhookDeliveriesRequest) (*dbtypes.ClaimWebhookDeliveriesResponse, error)
+	RecordWebhookDeliveryAttempt(ctx context.Context, request *dbtypes.RecordWebhookDeliveryAttemptRequest) error
+	ListWebhookDeliveries(ctx context.Context, request *dbtypes.ListWebhookDeliveriesRequest) (*dbtypes.ListWebhookDeliveriesResponse, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, request *dbtypes.ListWebhookDeliveryAttemptsRequest) (*dbtypes.ListWebhookDeliveryAttemptsResponse, error)
+	RetryWebhookDelivery(ctx context.Context, request *dbtypes.RetryWebhookDeliveryRequest) error
+
+	GetPermissionSets(ctx context.Context, request *dbtypes.GetPermissionSetsRequest) (*dbtypes.GetPermissionSetsResponse, error)
+	ListPermissions(ctx context.Context) (*dbtypes.ListPermissionsResponse, error)
+	GetUserPermissions(ctx context.Context, request *dbtypes.GetUserPermissionsRequest) (*dbtypes.GetUserPermissionsResponse, error)
+	GrantPermission(ctx context.Context, request *dbtypes.GrantPermissionRequest) error
+	RevokePermission(ctx context.Context, request *dbtypes.RevokePermissionRequest) error
+
+	GetUserDormitoryScopes(ctx context.Context, request *dbtypes.GetUserDormitoryScopesRequest) (*dbtypes.GetUserDormitoryScopesResponse, error)
+	AddDormitoryScope(ctx context.Context, request *dbtypes.AddDormitoryScopeRequest) error
+	RemoveDormitoryScope(ctx context.Context, request *dbtypes.RemoveDormitoryScopeRequest) error
+
+	CreateRefreshToken(ctx context.Context, request *dbtypes.CreateRefreshTokenRequest) (*dbtypes.CreateRefreshTokenResponse, error)
+	GetRefreshTokenByHash(ctx context.Context, request *dbtypes.GetRefreshTokenByHashRequest) (*dbtypes.GetRefreshTokenResponse, error)
+	RotateRefreshToken(ctx context.Context, request *dbtypes.RotateRefreshTokenRequest) error
+	IsSessionActive(ctx context.Context, request *dbtypes.IsSessionActiveRequest) (*dbtypes.IsSessionActiveResponse, error)
+	RevokeRefreshTokenFamily(ctx context.Context, request *dbtypes.RevokeRefreshTokenFamilyRequest) error
+	RevokeUserRefreshTokens(ctx context.Context, request *dbtypes.RevokeUserRefreshTokensRequest) error
+
+	CreatePasswordResetToken(ctx context.Context, request *dbtypes.CreatePasswordResetTokenRequest) error
+	ResetPassword(ctx context.Context, request *dbtypes.ResetPasswordRequest) (*dbtypes.ResetPasswordResponse, error)
+
+	CreateEmailVerificationToken(ctx context.Context, request *dbtypes.CreateEmailVerificationTokenRequest) error
+	VerifyEmail(ctx context.Context, request *dbtypes.VerifyEmailRequest) (*dbtypes.VerifyEmailResponse, error)
 }
 
 func New(db *sql.DB) Repository {
@@ -47,6 +103,25 @@ func New(db *sql.DB) Repository {
 	}
 }
 
+func (c *Database) withTx(ctx context.Context, fn func(tx Driver) error) error {
+	tx, err := c.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%w: error starting transaction: %v", dberrors.ErrInternal, err)
+	}
+
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%w: error committing transaction: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
 func InitDb(cfg config.DataBaseConfig) (*sql.DB, error) {
 	connStr := cfg.GetConnectionString()
 
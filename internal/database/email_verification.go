@@ -0,0 +1,206 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/google/uuid"
+)
+
+func (c *Database) CreateEmailVerificationToken(
+	ctx context.Context,
+	request *dbtypes.CreateEmailVerificationTokenRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		if err := c.expireEmailVerificationTokens(ctx, tx, request.UserId); err != nil {
+			return err
+		}
+
+		return c.createEmailVerificationToken(ctx, tx, request)
+	})
+}
+
+func (c *Database) createEmailVerificationToken(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.CreateEmailVerificationTokenRequest,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		verificationTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.EmailVerificationTokensTableName)
+	)
+
+	queryBuilder := psql.Insert(verificationTokensTable).
+		Columns(
+			"id", "user_id", "token_hash", "expires_at",
+		).
+		Values(
+			uuid.NewString(), request.UserId, request.TokenHash, request.ExpiresAt,
+		)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building create email verification token query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing create email verification token query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+// expireEmailVerificationTokens marks every unused token of the user as used,
+// so only the most recently sent link works.
+func (c *Database) expireEmailVerificationTokens(
+	ctx context.Context,
+	driver Driver,
+	userId string,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		verificationTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.EmailVerificationTokensTableName)
+	)
+
+	queryBuilder := psql.Update(verificationTokensTable).
+		Set("used_at", time.Now().UTC()).
+		Where(squirrel.Eq{
+			"user_id": userId,
+			"used_at": nil,
+		})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building expire email verification tokens query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing expire email verification tokens query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+func (c *Database) VerifyEmail(
+	ctx context.Context,
+	request *dbtypes.VerifyEmailRequest,
+) (*dbtypes.VerifyEmailResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var resp *dbtypes.VerifyEmailResponse
+
+	err := c.withTx(ctx, func(tx Driver) error {
+		userId, err := c.consumeEmailVerificationToken(ctx, tx, request.TokenHash)
+		if err != nil {
+			return err
+		}
+
+		if err := c.markEmailVerified(ctx, tx, userId); err != nil {
+			return err
+		}
+
+		resp = &dbtypes.VerifyEmailResponse{
+			UserId: userId,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) consumeEmailVerificationToken(
+	ctx context.Context,
+	driver Driver,
+	tokenHash string,
+) (string, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		verificationTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.EmailVerificationTokensTableName)
+	)
+
+	now := time.Now().UTC()
+
+	queryBuilder := psql.Update(verificationTokensTable).
+		Set("used_at", now).
+		Where(squirrel.Eq{
+			"token_hash": tokenHash,
+			"used_at":    nil,
+		}).
+		Where(squirrel.Gt{"expires_at": now}).
+		Suffix("RETURNING user_id")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return "", fmt.Errorf("%w: error building consume email verification token query: %v", dberrors.ErrInternal, err)
+	}
+
+	var userId string
+	err = driver.QueryRowContext(ctx, query, args...).Scan(&userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("%w: email verification token not found, used or expired", dberrors.ErrNotFound)
+		}
+
+		return "", fmt.Errorf("%w: error executing consume email verification token query: %v", dberrors.ErrInternal, err)
+	}
+
+	return userId, nil
+}
+
+func (c *Database) markEmailVerified(
+	ctx context.Context,
+	driver Driver,
+	userId string,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	queryBuilder := psql.Update(usersTable).
+		Set("email_verified_at", squirrel.Expr("COALESCE(email_verified_at, ?)", time.Now().UTC())).
+		Where(squirrel.Eq{"id": userId})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building mark email verified query: %v", dberrors.ErrInternal, err)
+	}
+
+	res, err := driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing mark email verified query: %v", dberrors.ErrInternal, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: error getting affected rows: %v", dberrors.ErrInternal, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
+	}
+
+	return nil
+}
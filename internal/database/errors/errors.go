@@ -3,7 +3,8 @@ package dberrors
 import "errors"
 
 const (
-	PGErrUniqueViolation = "23505"
+	PGErrUniqueViolation     = "23505"
+	PGErrForeignKeyViolation = "23503"
 )
 
 var (
@@ -11,4 +12,5 @@ var (
 	ErrConflict   = errors.New("conflict")
 	ErrNotFound   = errors.New("not found")
 	ErrBadRequest = errors.New("bad request")
+	ErrForbidden  = errors.New("forbidden")
 )
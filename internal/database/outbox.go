@@ -0,0 +1,213 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/google/uuid"
+)
+
+// createOutboxEvent writes an event about the user to the outbox. It has to
+// run in the transaction of the change it describes, so the event is
+// published if and only if the change is committed. The event is built from
+// the row of the user, which has to exist; an empty dormitoryId is taken from
+// it.
+func (c *Database) createOutboxEvent(
+	ctx context.Context,
+	driver Driver,
+	userId string,
+	dormitoryId string,
+	payload dbtypes.OutboxPayload,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		outboxEventsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.OutboxEventsTableName)
+		usersTable        = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: error encoding outbox event payload: %v", dberrors.ErrInternal, err)
+	}
+
+	now := time.Now().UTC()
+
+	// keeps the default placeholders, the insert numbers them
+	source := squirrel.
+		Select().
+		Column("?::uuid", uuid.NewString()).
+		Column("?", payload.EventType()).
+		Column("u.id").
+		Column("COALESCE(?, u.dormitory_id)", nullableString(dormitoryId)).
+		Column("?::jsonb", string(data)).
+		Column("?::timestamp", now).
+		Column("?::timestamp", now).
+		From(usersTable + " u").
+		Where(squirrel.Eq{"u.id": userId})
+
+	queryBuilder := psql.Insert(outboxEventsTable).
+		Columns("event_id", "type", "user_id", "dormitory_id", "payload", "created_at", "next_attempt_at").
+		Select(source)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building create outbox event query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing create outbox event query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+// ClaimOutboxEvents leases the oldest due events. Relays running side by side
+// skip the rows locked by each other, so every event is handed to one relay
+// at a time.
+func (c *Database) ClaimOutboxEvents(
+	ctx context.Context,
+	request *dbtypes.ClaimOutboxEventsRequest,
+) (*dbtypes.ClaimOutboxEventsResponse, error) {
+	if request == nil || request.Limit == 0 {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		outboxEventsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.OutboxEventsTableName)
+	)
+
+	// keeps the default placeholders, the update numbers them
+	due := squirrel.
+		Select("id").
+		From(outboxEventsTable).
+		Where(squirrel.Eq{"published_at": nil}).
+		Where(squirrel.LtOrEq{"next_attempt_at": request.Now}).
+		OrderBy("id").
+		Limit(request.Limit).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	queryBuilder := psql.Update(outboxEventsTable).
+		Set("attempts", squirrel.Expr("attempts + 1")).
+		Set("next_attempt_at", request.Now.Add(request.Lease)).
+		Where(squirrel.Expr("id IN (?)", due)).
+		Suffix("RETURNING id, event_id, type, user_id, dormitory_id, payload, created_at, attempts")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building claim outbox events query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing claim outbox events query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	events := make([]dbtypes.OutboxEvent, 0, request.Limit)
+	for rows.Next() {
+		var event dbtypes.OutboxEvent
+
+		err := rows.Scan(
+			&event.Id, &event.EventId, &event.Type, &event.UserId, &event.DormitoryId,
+			&event.Payload, &event.CreatedAt, &event.Attempts,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning outbox event: %v", dberrors.ErrInternal, err)
+		}
+
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating outbox events: %v", dberrors.ErrInternal, err)
+	}
+
+	// RETURNING does not keep the order of the subquery
+	slices.SortFunc(events, func(a, b dbtypes.OutboxEvent) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+
+	return &dbtypes.ClaimOutboxEventsResponse{
+		Events: events,
+	}, nil
+}
+
+func (c *Database) MarkOutboxEventPublished(
+	ctx context.Context,
+	request *dbtypes.MarkOutboxEventPublishedRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		outboxEventsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.OutboxEventsTableName)
+	)
+
+	queryBuilder := psql.Update(outboxEventsTable).
+		Set("published_at", request.PublishedAt).
+		Set("last_error", nil).
+		Where(squirrel.Eq{"id": request.Id})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building mark outbox event published query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = c.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing mark outbox event published query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+// MarkOutboxEventFailed records a failed delivery and schedules the next one.
+func (c *Database) MarkOutboxEventFailed(
+	ctx context.Context,
+	request *dbtypes.MarkOutboxEventFailedRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		outboxEventsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.OutboxEventsTableName)
+	)
+
+	queryBuilder := psql.Update(outboxEventsTable).
+		Set("next_attempt_at", request.NextAttemptAt).
+		Set("last_error", request.Error).
+		Where(squirrel.Eq{
+			"id":           request.Id,
+			"published_at": nil,
+		})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building mark outbox event failed query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = c.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing mark outbox event failed query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/google/uuid"
+)
+
+func (c *Database) CreatePasswordResetToken(
+	ctx context.Context,
+	request *dbtypes.CreatePasswordResetTokenRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		if err := c.expirePasswordResetTokens(ctx, tx, request.UserId); err != nil {
+			return err
+		}
+
+		return c.createPasswordResetToken(ctx, tx, request)
+	})
+}
+
+func (c *Database) createPasswordResetToken(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.CreatePasswordResetTokenRequest,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		resetTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.PasswordResetTokensTableName)
+	)
+
+	queryBuilder := psql.Insert(resetTokensTable).
+		Columns(
+			"id", "user_id", "token_hash", "expires_at",
+		).
+		Values(
+			uuid.NewString(), request.UserId, request.TokenHash, request.ExpiresAt,
+		)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building create password reset token query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing create password reset token query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+// expirePasswordResetTokens marks every unused token of the user as used, so
+// only the most recently requested link works.
+func (c *Database) expirePasswordResetTokens(
+	ctx context.Context,
+	driver Driver,
+	userId string,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		resetTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.PasswordResetTokensTableName)
+	)
+
+	queryBuilder := psql.Update(resetTokensTable).
+		Set("used_at", time.Now().UTC()).
+		Where(squirrel.Eq{
+			"user_id": userId,
+			"used_at": nil,
+		})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building expire password reset tokens query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing expire password reset tokens query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+func (c *Database) ResetPassword(
+	ctx context.Context,
+	request *dbtypes.ResetPasswordRequest,
+) (*dbtypes.ResetPasswordResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var resp *dbtypes.ResetPasswordResponse
+
+	err := c.withTx(ctx, func(tx Driver) error {
+		userId, err := c.consumePasswordResetToken(ctx, tx, request.TokenHash)
+		if err != nil {
+			return err
+		}
+
+		if err := c.updatePassword(ctx, tx, userId, request.Password); err != nil {
+			return err
+		}
+
+		if err := c.revokeRefreshTokens(ctx, tx, squirrel.Eq{"user_id": userId}); err != nil {
+			return err
+		}
+
+		resp = &dbtypes.ResetPasswordResponse{
+			UserId: userId,
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) consumePasswordResetToken(
+	ctx context.Context,
+	driver Driver,
+	tokenHash string,
+) (string, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		resetTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.PasswordResetTokensTableName)
+	)
+
+	now := time.Now().UTC()
+
+	queryBuilder := psql.Update(resetTokensTable).
+		Set("used_at", now).
+		Where(squirrel.Eq{
+			"token_hash": tokenHash,
+			"used_at":    nil,
+		}).
+		Where(squirrel.Gt{"expires_at": now}).
+		Suffix("RETURNING user_id")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return "", fmt.Errorf("%w: error building consume password reset token query: %v", dberrors.ErrInternal, err)
+	}
+
+	var userId string
+	err = driver.QueryRowContext(ctx, query, args...).Scan(&userId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("%w: password reset token not found, used or expired", dberrors.ErrNotFound)
+		}
+
+		return "", fmt.Errorf("%w: error executing consume password reset token query: %v", dberrors.ErrInternal, err)
+	}
+
+	return userId, nil
+}
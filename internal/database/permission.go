@@ -0,0 +1,552 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/lib/pq"
+)
+
+// GetPermissionSets loads what permission checks of many users need in two
+// queries: the asked permissions of their roles and grants valid right now,
+// and the dormitories of their scopes.
+func (c *Database) GetPermissionSets(
+	ctx context.Context,
+	request *dbtypes.GetPermissionSetsRequest,
+) (*dbtypes.GetPermissionSetsResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	resp := &dbtypes.GetPermissionSetsResponse{
+		RolePermissions: make(map[string]map[string]bool),
+		Grants:          make(map[string]map[string]bool),
+		Scopes:          make(map[string]map[string]bool),
+	}
+
+	if len(request.UserIds) == 0 {
+		return resp, nil
+	}
+
+	if err := c.loadPermissions(ctx, c.db, request, resp); err != nil {
+		return nil, err
+	}
+
+	if err := c.loadDormitoryScopes(ctx, c.db, request.UserIds, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) loadPermissions(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.GetPermissionSetsRequest,
+	resp *dbtypes.GetPermissionSetsResponse,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		rolePermissionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RolePermissionsTableName)
+		userPermissionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserPermissionsTableName)
+	)
+
+	// keeps the default placeholders, the outer query numbers them
+	grantSubQuery := squirrel.
+		Select("'grant'", "user_id::text", "permission").
+		From(userPermissionsTable).
+		Where("user_id = ANY(?)", pq.Array(request.UserIds)).
+		Where("permission = ANY(?)", pq.Array(request.Permissions)).
+		Where(grantValidAt(time.Now().UTC()))
+
+	queryBuilder := psql.
+		Select("'role'", "role", "permission").
+		From(rolePermissionsTable).
+		Where("role = ANY(?)", pq.Array(request.Roles)).
+		Where("permission = ANY(?)", pq.Array(request.Permissions)).
+		Suffix("UNION ALL ?", grantSubQuery)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building load permissions query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing load permissions query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var source, holder, permission string
+		if err := rows.Scan(&source, &holder, &permission); err != nil {
+			return fmt.Errorf("%w: error scanning permission: %v", dberrors.ErrInternal, err)
+		}
+
+		sets := resp.RolePermissions
+		if source == "grant" {
+			sets = resp.Grants
+		}
+
+		if sets[holder] == nil {
+			sets[holder] = make(map[string]bool)
+		}
+		sets[holder][permission] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: error iterating permissions: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+func (c *Database) loadDormitoryScopes(
+	ctx context.Context,
+	driver Driver,
+	userIds []string,
+	resp *dbtypes.GetPermissionSetsResponse,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		scopesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserDormitoryScopesTableName)
+	)
+
+	queryBuilder := psql.
+		Select("user_id", "dormitory_id").
+		From(scopesTable).
+		Where("user_id = ANY(?)", pq.Array(userIds))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building load dormitory scopes query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing load dormitory scopes query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userId, dormitoryId string
+		if err := rows.Scan(&userId, &dormitoryId); err != nil {
+			return fmt.Errorf("%w: error scanning dormitory scope: %v", dberrors.ErrInternal, err)
+		}
+
+		if resp.Scopes[userId] == nil {
+			resp.Scopes[userId] = make(map[string]bool)
+		}
+		resp.Scopes[userId][dormitoryId] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: error iterating dormitory scopes: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+func (c *Database) ListPermissions(ctx context.Context) (*dbtypes.ListPermissionsResponse, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		permissionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.PermissionsTableName)
+	)
+
+	queryBuilder := psql.
+		Select("name", "description", "grantable").
+		From(permissionsTable).
+		OrderBy("name")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building list permissions query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing list permissions query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var permissions []dbtypes.Permission
+	for rows.Next() {
+		var permission dbtypes.Permission
+		if err := rows.Scan(&permission.Name, &permission.Description, &permission.Grantable); err != nil {
+			return nil, fmt.Errorf("%w: error scanning permission: %v", dberrors.ErrInternal, err)
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating permissions: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.ListPermissionsResponse{
+		Permissions: permissions,
+	}, nil
+}
+
+func (c *Database) GetUserPermissions(
+	ctx context.Context,
+	request *dbtypes.GetUserPermissionsRequest,
+) (*dbtypes.GetUserPermissionsResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	rolePermissions, err := c.listRolePermissions(ctx, c.db, request.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	grants, err := c.listPermissionGrants(ctx, c.db, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dbtypes.GetUserPermissionsResponse{
+		RolePermissions: rolePermissions,
+		Grants:          grants,
+	}, nil
+}
+
+func (c *Database) listRolePermissions(
+	ctx context.Context,
+	driver Driver,
+	role string,
+) ([]string, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		rolePermissionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RolePermissionsTableName)
+	)
+
+	queryBuilder := psql.
+		Select("permission").
+		From(rolePermissionsTable).
+		Where(squirrel.Eq{"role": role}).
+		OrderBy("permission")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building list role permissions query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing list role permissions query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var permissions []string
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, fmt.Errorf("%w: error scanning role permission: %v", dberrors.ErrInternal, err)
+		}
+
+		permissions = append(permissions, permission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating role permissions: %v", dberrors.ErrInternal, err)
+	}
+
+	return permissions, nil
+}
+
+func (c *Database) listPermissionGrants(
+	ctx context.Context,
+	driver Driver,
+	userId string,
+) ([]dbtypes.PermissionGrant, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		userPermissionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserPermissionsTableName)
+	)
+
+	queryBuilder := psql.
+		Select("user_id", "permission", "granted_by", "granted_at", "valid_from", "valid_until").
+		From(userPermissionsTable).
+		Where(squirrel.Eq{"user_id": userId}).
+		OrderBy("permission")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building list permission grants query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing list permission grants query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var grants []dbtypes.PermissionGrant
+	for rows.Next() {
+		grant, err := scanPermissionGrant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning permission grant: %v", dberrors.ErrInternal, err)
+		}
+
+		grants = append(grants, *grant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating permission grants: %v", dberrors.ErrInternal, err)
+	}
+
+	return grants, nil
+}
+
+// scanPermissionGrant reads the columns user_id, permission, granted_by,
+// granted_at, valid_from and valid_until.
+func scanPermissionGrant(row rowScanner) (*dbtypes.PermissionGrant, error) {
+	var (
+		grant      dbtypes.PermissionGrant
+		grantedBy  sql.NullString
+		validFrom  sql.NullTime
+		validUntil sql.NullTime
+	)
+
+	err := row.Scan(&grant.UserId, &grant.Permission, &grantedBy, &grant.GrantedAt, &validFrom, &validUntil)
+	if err != nil {
+		return nil, err
+	}
+
+	if grantedBy.Valid {
+		grant.GrantedBy = &grantedBy.String
+	}
+
+	if validFrom.Valid {
+		grant.ValidFrom = &validFrom.Time
+	}
+
+	if validUntil.Valid {
+		grant.ValidUntil = &validUntil.Time
+	}
+
+	return &grant, nil
+}
+
+// grantValidAt matches permission grants whose validity covers at.
+func grantValidAt(at time.Time) squirrel.And {
+	return squirrel.And{
+		squirrel.Or{squirrel.Eq{"valid_from": nil}, squirrel.LtOrEq{"valid_from": at}},
+		squirrel.Or{squirrel.Eq{"valid_until": nil}, squirrel.Gt{"valid_until": at}},
+	}
+}
+
+// GrantPermission gives a grantable permission to a user of the dormitory.
+// Granting a permission the user already has replaces its validity.
+func (c *Database) GrantPermission(
+	ctx context.Context,
+	request *dbtypes.GrantPermissionRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		if err := c.checkDormitoryUser(ctx, tx, request.DormitoryId, request.UserId); err != nil {
+			return err
+		}
+
+		if err := c.checkPermissionGrantable(ctx, tx, request.Permission); err != nil {
+			return err
+		}
+
+		if err := c.createPermissionGrant(ctx, tx, request); err != nil {
+			return err
+		}
+
+		return c.createUserChange(ctx, tx, &dbtypes.UserChange{
+			UserId:      request.UserId,
+			DormitoryId: request.DormitoryId,
+			Kind:        dbtypes.UserChangeKindPermission,
+			NewValue:    &request.Permission,
+		})
+	})
+}
+
+func (c *Database) checkDormitoryUser(
+	ctx context.Context,
+	driver Driver,
+	dormitoryId string,
+	userId string,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	queryBuilder := psql.
+		Select("1").
+		From(usersTable).
+		Where(squirrel.Eq{
+			"id":           userId,
+			"dormitory_id": dormitoryId,
+		})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building check dormitory user query: %v", dberrors.ErrInternal, err)
+	}
+
+	var exists int
+	err = driver.QueryRowContext(ctx, query, args...).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
+		}
+
+		return fmt.Errorf("%w: error executing check dormitory user query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+func (c *Database) checkPermissionGrantable(
+	ctx context.Context,
+	driver Driver,
+	permission string,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		permissionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.PermissionsTableName)
+	)
+
+	queryBuilder := psql.
+		Select("grantable").
+		From(permissionsTable).
+		Where(squirrel.Eq{"name": permission})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building get permission query: %v", dberrors.ErrInternal, err)
+	}
+
+	var grantable bool
+	err = driver.QueryRowContext(ctx, query, args...).Scan(&grantable)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: permission not found", dberrors.ErrNotFound)
+		}
+
+		return fmt.Errorf("%w: error executing get permission query: %v", dberrors.ErrInternal, err)
+	}
+
+	if !grantable {
+		return fmt.Errorf("%w: permission %q comes only with a role", dberrors.ErrBadRequest, permission)
+	}
+
+	return nil
+}
+
+func (c *Database) createPermissionGrant(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.GrantPermissionRequest,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		userPermissionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserPermissionsTableName)
+	)
+
+	queryBuilder := psql.Insert(userPermissionsTable).
+		Columns("user_id", "permission", "granted_by", "valid_from", "valid_until").
+		Values(
+			request.UserId, request.Permission, nullableString(request.GrantedBy),
+			request.ValidFrom, request.ValidUntil,
+		).
+		Suffix(`ON CONFLICT (user_id, permission) DO UPDATE SET
+			granted_by = EXCLUDED.granted_by,
+			granted_at = CURRENT_TIMESTAMP,
+			valid_from = EXCLUDED.valid_from,
+			valid_until = EXCLUDED.valid_until`)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building create permission grant query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing create permission grant query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+// RevokePermission removes a grant from a user of the dormitory. Permissions
+// that come with the role of the user are not affected.
+func (c *Database) RevokePermission(
+	ctx context.Context,
+	request *dbtypes.RevokePermissionRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		usersTable           = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+		userPermissionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserPermissionsTableName)
+	)
+
+	dormitoryUsers := squirrel.
+		Select("id").
+		From(usersTable).
+		Where(squirrel.Eq{"dormitory_id": request.DormitoryId})
+
+	queryBuilder := psql.Delete(userPermissionsTable).
+		Where(squirrel.Eq{
+			"user_id":    request.UserId,
+			"permission": request.Permission,
+		}).
+		Where(squirrel.Expr("user_id IN (?)", dormitoryUsers))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building revoke permission query: %v", dberrors.ErrInternal, err)
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("%w: error executing revoke permission query: %v", dberrors.ErrInternal, err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%w: error getting affected rows: %v", dberrors.ErrInternal, err)
+		}
+
+		if affected == 0 {
+			return fmt.Errorf("%w: permission grant not found", dberrors.ErrNotFound)
+		}
+
+		return c.createUserChange(ctx, tx, &dbtypes.UserChange{
+			UserId:      request.UserId,
+			DormitoryId: request.DormitoryId,
+			Kind:        dbtypes.UserChangeKindPermission,
+			OldValue:    &request.Permission,
+		})
+	})
+}
@@ -0,0 +1,379 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/google/uuid"
+)
+
+func (c *Database) CreateRefreshToken(
+	ctx context.Context,
+	request *dbtypes.CreateRefreshTokenRequest,
+) (*dbtypes.CreateRefreshTokenResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	if !request.Login {
+		return c.createRefreshToken(ctx, c.db, request)
+	}
+
+	var resp *dbtypes.CreateRefreshTokenResponse
+
+	err := c.withTx(ctx, func(tx Driver) error {
+		var err error
+		resp, err = c.createRefreshToken(ctx, tx, request)
+		if err != nil {
+			return err
+		}
+
+		return c.createOutboxEvent(ctx, tx, request.UserId, "", dbtypes.UserLoggedInPayload{
+			ClientId:  request.ClientId,
+			SessionId: request.FamilyId,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) createRefreshToken(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.CreateRefreshTokenRequest,
+) (*dbtypes.CreateRefreshTokenResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		refreshTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RefreshTokensTableName)
+	)
+
+	id := uuid.NewString()
+
+	queryBuilder := psql.Insert(refreshTokensTable).
+		Columns(
+			"id", "user_id", "family_id", "client_id", "token_hash", "expires_at",
+		).
+		Values(
+			id, request.UserId, request.FamilyId, request.ClientId, request.TokenHash, request.ExpiresAt,
+		)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building create refresh token query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing create refresh token query: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.CreateRefreshTokenResponse{
+		Id: id,
+	}, nil
+}
+
+func (c *Database) GetRefreshTokenByHash(
+	ctx context.Context,
+	request *dbtypes.GetRefreshTokenByHashRequest,
+) (*dbtypes.GetRefreshTokenResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	resp, err := c.getRefreshTokenByHash(ctx, c.db, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) getRefreshTokenByHash(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.GetRefreshTokenByHashRequest,
+) (*dbtypes.GetRefreshTokenResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		refreshTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RefreshTokensTableName)
+	)
+
+	queryBuilder := psql.
+		Select(
+			"id", "user_id", "family_id", "client_id", "token_hash",
+			"expires_at", "created_at", "used_at", "revoked_at",
+		).
+		From(refreshTokensTable).
+		Where(squirrel.Eq{"token_hash": request.TokenHash}).
+		Limit(1)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building get refresh token query: %v", dberrors.ErrInternal, err)
+	}
+
+	var (
+		token     dbtypes.RefreshToken
+		usedAt    sql.NullTime
+		revokedAt sql.NullTime
+	)
+	err = driver.QueryRowContext(ctx, query, args...).Scan(
+		&token.Id,
+		&token.UserId,
+		&token.FamilyId,
+		&token.ClientId,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&usedAt,
+		&revokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: refresh token not found", dberrors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error executing get refresh token query: %v", dberrors.ErrInternal, err)
+	}
+
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+
+	return &dbtypes.GetRefreshTokenResponse{
+		Token: &token,
+	}, nil
+}
+
+func (c *Database) RotateRefreshToken(
+	ctx context.Context,
+	request *dbtypes.RotateRefreshTokenRequest,
+) error {
+	if request == nil || request.NewToken == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		if err := c.markRefreshTokenUsed(ctx, tx, request.UsedTokenId); err != nil {
+			return err
+		}
+
+		if _, err := c.createRefreshToken(ctx, tx, request.NewToken); err != nil {
+			return err
+		}
+
+		return nil
+	})
+}
+
+func (c *Database) markRefreshTokenUsed(
+	ctx context.Context,
+	driver Driver,
+	tokenId string,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		refreshTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RefreshTokensTableName)
+	)
+
+	queryBuilder := psql.Update(refreshTokensTable).
+		Set("used_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{
+			"id":         tokenId,
+			"used_at":    nil,
+			"revoked_at": nil,
+		})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building mark refresh token used query: %v", dberrors.ErrInternal, err)
+	}
+
+	res, err := driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing mark refresh token used query: %v", dberrors.ErrInternal, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: error getting affected rows: %v", dberrors.ErrInternal, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%w: refresh token already used or revoked", dberrors.ErrConflict)
+	}
+
+	return nil
+}
+
+func (c *Database) IsSessionActive(
+	ctx context.Context,
+	request *dbtypes.IsSessionActiveRequest,
+) (*dbtypes.IsSessionActiveResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	resp, err := c.isSessionActive(ctx, c.db, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) isSessionActive(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.IsSessionActiveRequest,
+) (*dbtypes.IsSessionActiveResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		refreshTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RefreshTokensTableName)
+	)
+
+	// a family whose tokens all expired cannot be refreshed anymore
+	subQuery := psql.
+		Select("1").
+		From(refreshTokensTable).
+		Where(squirrel.Eq{
+			"family_id":  request.FamilyId,
+			"revoked_at": nil,
+		}).
+		Where(squirrel.Gt{"expires_at": time.Now().UTC()})
+
+	queryBuilder := psql.Select().Column(squirrel.Expr("EXISTS(?)", subQuery))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building is session active query: %v", dberrors.ErrInternal, err)
+	}
+
+	var active bool
+	err = driver.QueryRowContext(ctx, query, args...).Scan(&active)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing is session active query: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.IsSessionActiveResponse{
+		Active: active,
+	}, nil
+}
+
+func (c *Database) RevokeRefreshTokenFamily(
+	ctx context.Context,
+	request *dbtypes.RevokeRefreshTokenFamilyRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		return c.revokeRefreshTokens(ctx, tx, squirrel.Eq{"family_id": request.FamilyId})
+	})
+}
+
+func (c *Database) RevokeUserRefreshTokens(
+	ctx context.Context,
+	request *dbtypes.RevokeUserRefreshTokensRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		return c.revokeRefreshTokens(ctx, tx, squirrel.Eq{"user_id": request.UserId})
+	})
+}
+
+// revokeRefreshTokens revokes the matching sessions and logs a change for
+// every user who lost one. Call it inside a transaction so the log matches
+// the revocations.
+func (c *Database) revokeRefreshTokens(
+	ctx context.Context,
+	driver Driver,
+	filter squirrel.Sqlizer,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		refreshTokensTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RefreshTokensTableName)
+	)
+
+	queryBuilder := psql.Update(refreshTokensTable).
+		Set("revoked_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(filter).
+		Where(squirrel.Eq{"revoked_at": nil}).
+		Suffix("RETURNING user_id")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building revoke refresh tokens query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing revoke refresh tokens query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var userIds []string
+	for rows.Next() {
+		var userId string
+		if err := rows.Scan(&userId); err != nil {
+			return fmt.Errorf("%w: error scanning revoked refresh token: %v", dberrors.ErrInternal, err)
+		}
+
+		if !slices.Contains(userIds, userId) {
+			userIds = append(userIds, userId)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%w: error iterating revoked refresh tokens: %v", dberrors.ErrInternal, err)
+	}
+
+	rows.Close()
+
+	for _, userId := range userIds {
+		err := c.createUserChange(ctx, driver, &dbtypes.UserChange{
+			UserId: userId,
+			Kind:   dbtypes.UserChangeKindSessionsRevoked,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,370 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/google/uuid"
+)
+
+// UpdateUserRole changes the role of a user inside their dormitory. It
+// refuses to demote the last active admin of the dormitory or a super admin
+// through the API, and to change a role a grant in effect overrides. Every
+// change is recorded in role_changes.
+func (c *Database) UpdateUserRole(
+	ctx context.Context,
+	request *dbtypes.UpdateUserRoleRequest,
+) (*dbtypes.UpdateUserRoleResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var resp *dbtypes.UpdateUserRoleResponse
+
+	err := c.withTx(ctx, func(tx Driver) error {
+		var err error
+		resp, err = c.changeUserRole(ctx, tx, request)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// changeUserRole is UpdateUserRole inside the transaction of the caller.
+func (c *Database) changeUserRole(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.UpdateUserRoleRequest,
+) (*dbtypes.UpdateUserRoleResponse, error) {
+	admins, err := c.lockDormitoryAdmins(ctx, driver, request.DormitoryId, request.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	oldRole := admins.role
+
+	// the grant would keep overriding the new role until it ends
+	if admins.granted {
+		return nil, fmt.Errorf("%w: the user has a role grant in effect, end it before changing the role", dberrors.ErrConflict)
+	}
+
+	if oldRole == constants.UserAdminRole && request.Role != constants.UserAdminRole && admins.otherAdmins == 0 {
+		return nil, fmt.Errorf("%w: cannot remove the last admin of the dormitory", dberrors.ErrConflict)
+	}
+
+	if oldRole == constants.UserSuperAdminRole && request.ActorSource != dbtypes.RoleChangeSourceCLI {
+		return nil, fmt.Errorf("%w: the role of a super admin can only be changed with authctl", dberrors.ErrConflict)
+	}
+
+	resp, err := c.updateUserRole(ctx, driver, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldRole == request.Role {
+		return resp, nil
+	}
+
+	err = c.createRoleChange(ctx, driver, &dbtypes.RoleChange{
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+		OldRole:     oldRole,
+		NewRole:     request.Role,
+		ActorId:     nullableString(request.ActorId),
+		ActorSource: request.ActorSource,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// dormitoryAdmins is what lockDormitoryAdmins finds out about a user and the
+// other admins of their dormitory.
+type dormitoryAdmins struct {
+	// role is stored on the user, effectiveRole accounts for their grants.
+	role          string
+	effectiveRole string
+	// granted tells whether a role grant of the user is in effect.
+	granted bool
+	// otherAdmins counts the other admins that are active and admins now
+	// and stay admins once their grants end. A status that has not expired
+	// yet keeps an admin from counting.
+	otherAdmins int
+}
+
+// lockDormitoryAdmins locks the user together with every admin of the
+// dormitory, always in id order so concurrent role changes cannot deadlock.
+// Admins only through a grant are not counted, the dormitory loses them
+// when the grant ends.
+func (c *Database) lockDormitoryAdmins(
+	ctx context.Context,
+	driver Driver,
+	dormitoryId string,
+	userId string,
+) (*dormitoryAdmins, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	now := time.Now().UTC()
+
+	queryBuilder := psql.
+		Select("id", "users.role AS stored_role").
+		Column(effectiveRoleColumn(now)).
+		Column(squirrel.Expr("EXISTS(?)", activeRoleGrant(now))).
+		Column(effectiveStatusExpr(now)).
+		From(usersTable).
+		Where(squirrel.Eq{"dormitory_id": dormitoryId}).
+		Where(squirrel.Or{
+			squirrel.Eq{"users.role": constants.UserAdminRole},
+			squirrel.Eq{"id": userId},
+		}).
+		OrderBy("id").
+		Suffix("FOR UPDATE OF users")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building lock dormitory admins query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing lock dormitory admins query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var (
+		admins dormitoryAdmins
+		found  bool
+	)
+	for rows.Next() {
+		var (
+			id, role, effectiveRole, status string
+			granted                         bool
+		)
+
+		if err := rows.Scan(&id, &role, &effectiveRole, &granted, &status); err != nil {
+			return nil, fmt.Errorf("%w: error scanning dormitory admin: %v", dberrors.ErrInternal, err)
+		}
+
+		if id == userId {
+			admins.role, admins.effectiveRole, admins.granted = role, effectiveRole, granted
+			found = true
+			continue
+		}
+
+		if status == constants.UserStatusActive && effectiveRole == constants.UserAdminRole {
+			admins.otherAdmins++
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating dormitory admins: %v", dberrors.ErrInternal, err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
+	}
+
+	return &admins, nil
+}
+
+func (c *Database) updateUserRole(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.UpdateUserRoleRequest,
+) (*dbtypes.UpdateUserRoleResponse, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	queryBuilder := psql.Update(usersTable).
+		Set("role", request.Role).
+		Where(squirrel.Eq{
+			"id":           request.UserId,
+			"dormitory_id": request.DormitoryId,
+		}).
+		Suffix("RETURNING " + strings.Join(adminUserColumns, ", "))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building update user role query: %v", dberrors.ErrInternal, err)
+	}
+
+	user, err := scanAdminUser(driver.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error executing update user role query: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.UpdateUserRoleResponse{
+		User: user,
+	}, nil
+}
+
+// createRoleChange records a role change in role_changes and in the user
+// change log and writes a user.role_changed event.
+func (c *Database) createRoleChange(
+	ctx context.Context,
+	driver Driver,
+	change *dbtypes.RoleChange,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		roleChangesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RoleChangesTableName)
+	)
+
+	queryBuilder := psql.Insert(roleChangesTable).
+		Columns(
+			"id", "user_id", "dormitory_id", "old_role", "new_role", "actor_id", "actor_source",
+		).
+		Values(
+			uuid.NewString(), change.UserId, change.DormitoryId, change.OldRole, change.NewRole,
+			change.ActorId, change.ActorSource,
+		)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building create role change query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing create role change query: %v", dberrors.ErrInternal, err)
+	}
+
+	err = c.createUserChange(ctx, driver, &dbtypes.UserChange{
+		UserId:      change.UserId,
+		DormitoryId: change.DormitoryId,
+		Kind:        dbtypes.UserChangeKindRole,
+		OldValue:    &change.OldRole,
+		NewValue:    &change.NewRole,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.createOutboxEvent(ctx, driver, change.UserId, change.DormitoryId, dbtypes.UserRoleChangedPayload{
+		OldRole:     change.OldRole,
+		NewRole:     change.NewRole,
+		ActorId:     change.ActorId,
+		ActorSource: change.ActorSource,
+	})
+}
+
+func (c *Database) ListRoleChanges(
+	ctx context.Context,
+	request *dbtypes.ListRoleChangesRequest,
+) (*dbtypes.ListRoleChangesResponse, error) {
+	if request == nil || request.DormitoryId == "" {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	resp, err := c.listRoleChanges(ctx, c.db, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) listRoleChanges(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.ListRoleChangesRequest,
+) (*dbtypes.ListRoleChangesResponse, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		roleChangesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RoleChangesTableName)
+	)
+
+	filter := squirrel.Eq{"dormitory_id": request.DormitoryId}
+	if request.UserId != "" {
+		filter["user_id"] = request.UserId
+	}
+
+	queryBuilder := psql.
+		Select("id", "user_id", "dormitory_id", "old_role", "new_role", "actor_id", "actor_source", "changed_at").
+		From(roleChangesTable).
+		Where(filter).
+		OrderBy("changed_at DESC", "id").
+		Limit(request.Limit).
+		Offset(request.Offset)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building list role changes query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing list role changes query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	changes := make([]dbtypes.RoleChange, 0, request.Limit)
+	for rows.Next() {
+		var (
+			change  dbtypes.RoleChange
+			actorId sql.NullString
+		)
+
+		err := rows.Scan(
+			&change.Id,
+			&change.UserId,
+			&change.DormitoryId,
+			&change.OldRole,
+			&change.NewRole,
+			&actorId,
+			&change.ActorSource,
+			&change.ChangedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning role change: %v", dberrors.ErrInternal, err)
+		}
+
+		if actorId.Valid {
+			change.ActorId = &actorId.String
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating role changes: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.ListRoleChangesResponse{
+		Changes: changes,
+	}, nil
+}
+
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return &value
+}
@@ -0,0 +1,504 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+var roleGrantColumns = []string{
+	"id", "user_id", "dormitory_id", "role", "valid_from", "valid_until",
+	"granted_by", "created_at", "started_at", "expired_at",
+}
+
+// effectiveRoleColumn selects the role a user has at the moment: the role of
+// the latest grant in effect, the role stored on the user otherwise. It is
+// meant for queries on the users table.
+func effectiveRoleColumn(now time.Time) squirrel.Sqlizer {
+	return squirrel.Alias(effectiveRole(now), "role")
+}
+
+// effectiveRole is effectiveRoleColumn without the alias, for filters and
+// sorting.
+func effectiveRole(now time.Time) squirrel.Sqlizer {
+	return squirrel.Expr("COALESCE((?), users.role)", activeRoleGrant(now))
+}
+
+// activeRoleGrant selects the role of the latest grant of the user in effect
+// at now. It is meant as a subquery of queries on the users table.
+func activeRoleGrant(now time.Time) squirrel.SelectBuilder {
+	roleGrantsTable := fmt.Sprintf("%s.%s", constants.SchemaName, constants.RoleGrantsTableName)
+
+	// keeps the default placeholders, the outer query numbers them
+	return squirrel.
+		Select("g.role").
+		From(roleGrantsTable + " g").
+		Where("g.user_id = users.id").
+		Where(squirrel.LtOrEq{"g.valid_from": now}).
+		Where(squirrel.Gt{"g.valid_until": now}).
+		OrderBy("g.valid_from DESC").
+		Limit(1)
+}
+
+func (c *Database) CreateRoleGrant(
+	ctx context.Context,
+	request *dbtypes.CreateRoleGrantRequest,
+) (*dbtypes.CreateRoleGrantResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var resp *dbtypes.CreateRoleGrantResponse
+
+	err := c.withTx(ctx, func(tx Driver) error {
+		admins, err := c.lockDormitoryAdmins(ctx, tx, request.DormitoryId, request.UserId)
+		if err != nil {
+			return err
+		}
+
+		// the grant would replace the super admin role until it ends
+		if admins.role == constants.UserSuperAdminRole {
+			return fmt.Errorf("%w: a super admin cannot be given a role grant", dberrors.ErrForbidden)
+		}
+
+		if admins.role == constants.UserAdminRole && request.Role != constants.UserAdminRole && admins.otherAdmins == 0 {
+			return fmt.Errorf("%w: cannot grant another role to the last admin of the dormitory", dberrors.ErrConflict)
+		}
+
+		resp, err = c.createRoleGrant(ctx, tx, request)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) createRoleGrant(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.CreateRoleGrantRequest,
+) (*dbtypes.CreateRoleGrantResponse, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		roleGrantsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RoleGrantsTableName)
+	)
+
+	queryBuilder := psql.Insert(roleGrantsTable).
+		Columns("user_id", "dormitory_id", "role", "valid_from", "valid_until", "granted_by").
+		Values(
+			request.UserId, request.DormitoryId, request.Role, request.ValidFrom, request.ValidUntil,
+			nullableString(request.GrantedBy),
+		).
+		Suffix("RETURNING " + strings.Join(roleGrantColumns, ", "))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building create role grant query: %v", dberrors.ErrInternal, err)
+	}
+
+	grant, err := scanRoleGrant(driver.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing create role grant query: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.CreateRoleGrantResponse{
+		Grant: grant,
+	}, nil
+}
+
+func (c *Database) ListRoleGrants(
+	ctx context.Context,
+	request *dbtypes.ListRoleGrantsRequest,
+) (*dbtypes.ListRoleGrantsResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		roleGrantsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RoleGrantsTableName)
+	)
+
+	queryBuilder := psql.
+		Select(roleGrantColumns...).
+		From(roleGrantsTable).
+		Where(squirrel.Eq{
+			"user_id":      request.UserId,
+			"dormitory_id": request.DormitoryId,
+		}).
+		OrderBy("valid_from DESC", "id")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building list role grants query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing list role grants query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var grants []dbtypes.RoleGrant
+	for rows.Next() {
+		grant, err := scanRoleGrant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning role grant: %v", dberrors.ErrInternal, err)
+		}
+
+		grants = append(grants, *grant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating role grants: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.ListRoleGrantsResponse{
+		Grants: grants,
+	}, nil
+}
+
+func (c *Database) EndRoleGrant(
+	ctx context.Context,
+	request *dbtypes.EndRoleGrantRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		roleGrantsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RoleGrantsTableName)
+	)
+
+	queryBuilder := psql.Update(roleGrantsTable).
+		Set("valid_until", squirrel.Expr("LEAST(valid_until, ?)", request.At)).
+		Where(squirrel.Eq{
+			"id":           request.Id,
+			"user_id":      request.UserId,
+			"dormitory_id": request.DormitoryId,
+			"expired_at":   nil,
+		})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building end role grant query: %v", dberrors.ErrInternal, err)
+	}
+
+	res, err := c.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing end role grant query: %v", dberrors.ErrInternal, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: error getting affected rows: %v", dberrors.ErrInternal, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%w: role grant not found or already expired", dberrors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// SweepGrants brings stored grants in line with the clock. Role grants that
+// took effect are marked started; role grants and permission grants that
+// ended are marked expired or deleted, and the sessions of their users are
+// revoked so tokens with the old role are not refreshed. Role grants that
+// start or end are recorded in role_changes, permission grants that end in
+// the user change log.
+func (c *Database) SweepGrants(
+	ctx context.Context,
+	request *dbtypes.SweepGrantsRequest,
+) (*dbtypes.SweepGrantsResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	resp := &dbtypes.SweepGrantsResponse{}
+
+	err := c.withTx(ctx, func(tx Driver) error {
+		started, err := c.startRoleGrants(ctx, tx, request.Now)
+		if err != nil {
+			return err
+		}
+
+		expired, err := c.expireRoleGrants(ctx, tx, request.Now)
+		if err != nil {
+			return err
+		}
+
+		permissionGrants, err := c.deleteExpiredPermissionGrants(ctx, tx, request.Now)
+		if err != nil {
+			return err
+		}
+
+		revokeUsers := make(map[string]bool)
+
+		for _, grant := range started {
+			err := c.createRoleChange(ctx, tx, &dbtypes.RoleChange{
+				UserId:      grant.UserId,
+				DormitoryId: grant.DormitoryId,
+				OldRole:     grant.userRole,
+				NewRole:     grant.Role,
+				ActorId:     grant.GrantedBy,
+				ActorSource: dbtypes.RoleChangeSourceGrant,
+			})
+			if err != nil {
+				return err
+			}
+
+			resp.StartedRoleGrants = append(resp.StartedRoleGrants, grant.RoleGrant)
+		}
+
+		for _, grant := range expired {
+			// a grant ended early before it started never took effect
+			if !grant.ValidFrom.Before(grant.ValidUntil) {
+				continue
+			}
+
+			// the whole period passed between two sweeps
+			if grant.StartedAt == nil {
+				err := c.createRoleChange(ctx, tx, &dbtypes.RoleChange{
+					UserId:      grant.UserId,
+					DormitoryId: grant.DormitoryId,
+					OldRole:     grant.userRole,
+					NewRole:     grant.Role,
+					ActorId:     grant.GrantedBy,
+					ActorSource: dbtypes.RoleChangeSourceGrant,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			err := c.createRoleChange(ctx, tx, &dbtypes.RoleChange{
+				UserId:      grant.UserId,
+				DormitoryId: grant.DormitoryId,
+				OldRole:     grant.Role,
+				NewRole:     grant.userRole,
+				ActorSource: dbtypes.RoleChangeSourceGrant,
+			})
+			if err != nil {
+				return err
+			}
+
+			revokeUsers[grant.UserId] = true
+			resp.ExpiredRoleGrants = append(resp.ExpiredRoleGrants, grant.RoleGrant)
+		}
+
+		for _, grant := range permissionGrants {
+			err := c.createUserChange(ctx, tx, &dbtypes.UserChange{
+				UserId:   grant.UserId,
+				Kind:     dbtypes.UserChangeKindPermission,
+				OldValue: &grant.Permission,
+			})
+			if err != nil {
+				return err
+			}
+
+			revokeUsers[grant.UserId] = true
+			resp.ExpiredPermissionGrants = append(resp.ExpiredPermissionGrants, grant)
+		}
+
+		for userId := range revokeUsers {
+			if err := c.revokeRefreshTokens(ctx, tx, squirrel.Eq{"user_id": userId}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// sweptRoleGrant is a role grant together with the role stored on its user.
+type sweptRoleGrant struct {
+	dbtypes.RoleGrant
+	userRole string
+}
+
+func (c *Database) startRoleGrants(
+	ctx context.Context,
+	driver Driver,
+	now time.Time,
+) ([]sweptRoleGrant, error) {
+	return c.markRoleGrants(ctx, driver, "started_at", now, squirrel.And{
+		squirrel.Eq{"g.started_at": nil, "g.expired_at": nil},
+		squirrel.LtOrEq{"g.valid_from": now},
+		squirrel.Gt{"g.valid_until": now},
+	})
+}
+
+func (c *Database) expireRoleGrants(
+	ctx context.Context,
+	driver Driver,
+	now time.Time,
+) ([]sweptRoleGrant, error) {
+	return c.markRoleGrants(ctx, driver, "expired_at", now, squirrel.And{
+		squirrel.Eq{"g.expired_at": nil},
+		squirrel.LtOrEq{"g.valid_until": now},
+	})
+}
+
+// markRoleGrants sets column to now on the role grants matching filter and
+// returns them.
+func (c *Database) markRoleGrants(
+	ctx context.Context,
+	driver Driver,
+	column string,
+	now time.Time,
+	filter squirrel.Sqlizer,
+) ([]sweptRoleGrant, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		roleGrantsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.RoleGrantsTableName)
+		usersTable      = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	returning := make([]string, 0, len(roleGrantColumns)+1)
+	for _, name := range roleGrantColumns {
+		returning = append(returning, "g."+name)
+	}
+
+	returning = append(returning, "u.role")
+
+	queryBuilder := psql.Update(roleGrantsTable+" g").
+		Set(column, now).
+		From(usersTable + " u").
+		Where("u.id = g.user_id").
+		Where(filter).
+		Suffix("RETURNING " + strings.Join(returning, ", "))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building mark role grants query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing mark role grants query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var grants []sweptRoleGrant
+	for rows.Next() {
+		var grant sweptRoleGrant
+
+		scanned, err := scanRoleGrant(rows, &grant.userRole)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning role grant: %v", dberrors.ErrInternal, err)
+		}
+
+		grant.RoleGrant = *scanned
+		grants = append(grants, grant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating role grants: %v", dberrors.ErrInternal, err)
+	}
+
+	return grants, nil
+}
+
+func (c *Database) deleteExpiredPermissionGrants(
+	ctx context.Context,
+	driver Driver,
+	now time.Time,
+) ([]dbtypes.PermissionGrant, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		userPermissionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserPermissionsTableName)
+	)
+
+	queryBuilder := psql.Delete(userPermissionsTable).
+		Where(squirrel.LtOrEq{"valid_until": now}).
+		Suffix("RETURNING user_id, permission, granted_by, granted_at, valid_from, valid_until")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building delete expired permission grants query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing delete expired permission grants query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var grants []dbtypes.PermissionGrant
+	for rows.Next() {
+		grant, err := scanPermissionGrant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning permission grant: %v", dberrors.ErrInternal, err)
+		}
+
+		grants = append(grants, *grant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating permission grants: %v", dberrors.ErrInternal, err)
+	}
+
+	return grants, nil
+}
+
+// scanRoleGrant reads roleGrantColumns followed by the extra destinations.
+func scanRoleGrant(row rowScanner, extra ...any) (*dbtypes.RoleGrant, error) {
+	var (
+		grant     dbtypes.RoleGrant
+		grantedBy sql.NullString
+		startedAt sql.NullTime
+		expiredAt sql.NullTime
+	)
+
+	err := row.Scan(append([]any{
+		&grant.Id,
+		&grant.UserId,
+		&grant.DormitoryId,
+		&grant.Role,
+		&grant.ValidFrom,
+		&grant.ValidUntil,
+		&grantedBy,
+		&grant.CreatedAt,
+		&startedAt,
+		&expiredAt,
+	}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if grantedBy.Valid {
+		grant.GrantedBy = &grantedBy.String
+	}
+
+	if startedAt.Valid {
+		grant.StartedAt = &startedAt.Time
+	}
+
+	if expiredAt.Valid {
+		grant.ExpiredAt = &expiredAt.Time
+	}
+
+	return &grant, nil
+}
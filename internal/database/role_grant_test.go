@@ -0,0 +1,41 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+)
+
+func TestEffectiveRoleColumn(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	query, args, err := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("users.id").
+		Column(effectiveRoleColumn(now)).
+		From("public.users").
+		Where(squirrel.Eq{"users.id": "user"}).
+		ToSql()
+	if err != nil {
+		t.Fatalf("ToSql() error = %v", err)
+	}
+
+	wantQuery := "SELECT users.id, (COALESCE((SELECT g.role FROM public.role_grants g " +
+		"WHERE g.user_id = users.id AND g.valid_from <= $1 AND g.valid_until > $2 " +
+		"ORDER BY g.valid_from DESC LIMIT 1), users.role)) AS role " +
+		"FROM public.users WHERE users.id = $3"
+	if query != wantQuery {
+		t.Errorf("query = %q, want %q", query, wantQuery)
+	}
+
+	wantArgs := []any{now, now, "user"}
+	if len(args) != len(wantArgs) {
+		t.Fatalf("args = %v, want %v", args, wantArgs)
+	}
+
+	for i := range wantArgs {
+		if args[i] != wantArgs[i] {
+			t.Errorf("args[%d] = %v, want %v", i, args[i], wantArgs[i])
+		}
+	}
+}
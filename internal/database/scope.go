@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/lib/pq"
+)
+
+func (c *Database) GetUserDormitoryScopes(
+	ctx context.Context,
+	request *dbtypes.GetUserDormitoryScopesRequest,
+) (*dbtypes.GetUserDormitoryScopesResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	resp, err := c.getUserDormitoryScopes(ctx, c.db, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) getUserDormitoryScopes(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.GetUserDormitoryScopesRequest,
+) (*dbtypes.GetUserDormitoryScopesResponse, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		scopesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserDormitoryScopesTableName)
+	)
+
+	queryBuilder := psql.
+		Select("user_id", "dormitory_id", "granted_by", "granted_at").
+		From(scopesTable).
+		Where(squirrel.Eq{"user_id": request.UserId}).
+		OrderBy("dormitory_id")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building get dormitory scopes query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing get dormitory scopes query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var scopes []dbtypes.DormitoryScope
+	for rows.Next() {
+		var (
+			scope     dbtypes.DormitoryScope
+			grantedBy sql.NullString
+		)
+
+		if err := rows.Scan(&scope.UserId, &scope.DormitoryId, &grantedBy, &scope.GrantedAt); err != nil {
+			return nil, fmt.Errorf("%w: error scanning dormitory scope: %v", dberrors.ErrInternal, err)
+		}
+
+		if grantedBy.Valid {
+			scope.GrantedBy = &grantedBy.String
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating dormitory scopes: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.GetUserDormitoryScopesResponse{
+		Scopes: scopes,
+	}, nil
+}
+
+// AddDormitoryScope lets the user act in one more dormitory. Adding a scope
+// the user already has is not an error.
+func (c *Database) AddDormitoryScope(
+	ctx context.Context,
+	request *dbtypes.AddDormitoryScopeRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		scopesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserDormitoryScopesTableName)
+	)
+
+	queryBuilder := psql.Insert(scopesTable).
+		Columns("user_id", "dormitory_id", "granted_by").
+		Values(request.UserId, request.DormitoryId, nullableString(request.GrantedBy)).
+		Suffix("ON CONFLICT (user_id, dormitory_id) DO NOTHING")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building add dormitory scope query: %v", dberrors.ErrInternal, err)
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			var pgErr *pq.Error
+			if errors.As(err, &pgErr) && pgErr.Code == dberrors.PGErrForeignKeyViolation {
+				return fmt.Errorf("%w: user or dormitory not found", dberrors.ErrNotFound)
+			}
+
+			return fmt.Errorf("%w: error executing add dormitory scope query: %v", dberrors.ErrInternal, err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%w: error getting affected rows: %v", dberrors.ErrInternal, err)
+		}
+
+		if affected == 0 {
+			return nil
+		}
+
+		return c.createUserChange(ctx, tx, &dbtypes.UserChange{
+			UserId:      request.UserId,
+			DormitoryId: request.DormitoryId,
+			Kind:        dbtypes.UserChangeKindDormitory,
+			NewValue:    &request.DormitoryId,
+		})
+	})
+}
+
+func (c *Database) RemoveDormitoryScope(
+	ctx context.Context,
+	request *dbtypes.RemoveDormitoryScopeRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		scopesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserDormitoryScopesTableName)
+	)
+
+	queryBuilder := psql.Delete(scopesTable).
+		Where(squirrel.Eq{
+			"user_id":      request.UserId,
+			"dormitory_id": request.DormitoryId,
+		})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building remove dormitory scope query: %v", dberrors.ErrInternal, err)
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("%w: error executing remove dormitory scope query: %v", dberrors.ErrInternal, err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("%w: error getting affected rows: %v", dberrors.ErrInternal, err)
+		}
+
+		if affected == 0 {
+			return fmt.Errorf("%w: dormitory scope not found", dberrors.ErrNotFound)
+		}
+
+		return c.createUserChange(ctx, tx, &dbtypes.UserChange{
+			UserId:      request.UserId,
+			DormitoryId: request.DormitoryId,
+			Kind:        dbtypes.UserChangeKindDormitory,
+			OldValue:    &request.DormitoryId,
+		})
+	})
+}
@@ -9,6 +9,12 @@ type User struct {
 	DormitoryId string
 	Role        string
 	CreatedAt   time.Time
+
+	EmailVerifiedAt *time.Time
+
+	Status          string
+	StatusReason    string
+	StatusExpiresAt *time.Time
 }
 
 type (
@@ -36,6 +42,24 @@ type (
 		DormitoryId string
 		Role        string
 		CreatedAt   time.Time
+
+		EmailVerifiedAt *time.Time
+
+		Status          string
+		StatusReason    string
+		StatusExpiresAt *time.Time
+	}
+)
+
+type (
+	// GetUsersByIdsRequest loads many users at once. Users that do not exist
+	// are left out of the response.
+	GetUsersByIdsRequest struct {
+		Ids []string
+	}
+
+	GetUsersByIdsResponse struct {
+		Users []User
 	}
 )
 
@@ -46,7 +70,34 @@ type (
 
 	GetUserInfoByIdResponse struct {
 		UserId      string
+		Email       string
 		DormitoryId string
 		Role        string
+		CreatedAt   time.Time
+
+		EmailVerifiedAt *time.Time
+
+		Status          string
+		StatusReason    string
+		StatusExpiresAt *time.Time
 	}
 )
+
+type (
+	GetUserPasswordRequest struct {
+		UserId string
+	}
+
+	GetUserPasswordResponse struct {
+		Password string
+	}
+)
+
+// UpdatePasswordRequest sets a new password hash. When RevokeOtherSessions
+// is set, every session of the user except KeepFamilyId is revoked.
+type UpdatePasswordRequest struct {
+	UserId              string
+	Password            string
+	RevokeOtherSessions bool
+	KeepFamilyId        string
+}
@@ -0,0 +1,19 @@
+package dbtypes
+
+import "time"
+
+type CreateEmailVerificationTokenRequest struct {
+	UserId    string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+type (
+	VerifyEmailRequest struct {
+		TokenHash string
+	}
+
+	VerifyEmailResponse struct {
+		UserId string
+	}
+)
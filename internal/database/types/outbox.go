@@ -0,0 +1,81 @@
+package dbtypes
+
+import "time"
+
+// Types of outbox events.
+const (
+	OutboxEventUserRegistered  = "user.registered"
+	OutboxEventUserLoggedIn    = "user.logged_in"
+	OutboxEventUserRoleChanged = "user.role_changed"
+)
+
+var OutboxEventTypes = []string{
+	OutboxEventUserRegistered,
+	OutboxEventUserLoggedIn,
+	OutboxEventUserRoleChanged,
+}
+
+// OutboxPayload is the body of an outbox event. It is stored as JSON.
+type OutboxPayload interface {
+	EventType() string
+}
+
+type UserRegisteredPayload struct {
+	Email string `json:"email"`
+}
+
+func (UserRegisteredPayload) EventType() string { return OutboxEventUserRegistered }
+
+type UserLoggedInPayload struct {
+	ClientId  string `json:"client_id,omitempty"`
+	SessionId string `json:"session_id"`
+}
+
+func (UserLoggedInPayload) EventType() string { return OutboxEventUserLoggedIn }
+
+type UserRoleChangedPayload struct {
+	OldRole     string  `json:"old_role"`
+	NewRole     string  `json:"new_role"`
+	ActorId     *string `json:"actor_id,omitempty"`
+	ActorSource string  `json:"actor_source"`
+}
+
+func (UserRoleChangedPayload) EventType() string { return OutboxEventUserRoleChanged }
+
+type OutboxEvent struct {
+	Id          int64
+	EventId     string
+	Type        string
+	UserId      string
+	DormitoryId string
+	Payload     []byte
+	CreatedAt   time.Time
+	// Attempts counts deliveries started, including the current one.
+	Attempts int
+}
+
+type (
+	// ClaimOutboxEventsRequest leases up to Limit events that are due at Now.
+	// A leased event is not handed out again until Lease has passed, so an
+	// event of a relay that died is picked up by another one later.
+	ClaimOutboxEventsRequest struct {
+		Now   time.Time
+		Lease time.Duration
+		Limit uint64
+	}
+
+	ClaimOutboxEventsResponse struct {
+		Events []OutboxEvent
+	}
+)
+
+type MarkOutboxEventPublishedRequest struct {
+	Id          int64
+	PublishedAt time.Time
+}
+
+type MarkOutboxEventFailedRequest struct {
+	Id            int64
+	NextAttemptAt time.Time
+	Error         string
+}
@@ -0,0 +1,20 @@
+package dbtypes
+
+import "time"
+
+type CreatePasswordResetTokenRequest struct {
+	UserId    string
+	TokenHash string
+	ExpiresAt time.Time
+}
+
+type (
+	ResetPasswordRequest struct {
+		TokenHash string
+		Password  string
+	}
+
+	ResetPasswordResponse struct {
+		UserId string
+	}
+)
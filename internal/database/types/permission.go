@@ -0,0 +1,73 @@
+package dbtypes
+
+import "time"
+
+type Permission struct {
+	Name        string
+	Description string
+	Grantable   bool
+}
+
+// PermissionGrant is a permission given to one user on top of their role.
+type PermissionGrant struct {
+	UserId     string
+	Permission string
+	GrantedBy  *string
+	GrantedAt  time.Time
+	// ValidFrom and ValidUntil bound the grant in time when set.
+	ValidFrom  *time.Time
+	ValidUntil *time.Time
+}
+
+type (
+	// GetPermissionSetsRequest loads what permission checks of the users
+	// need, limited to the asked roles and permissions.
+	GetPermissionSetsRequest struct {
+		UserIds     []string
+		Roles       []string
+		Permissions []string
+	}
+
+	// GetPermissionSetsResponse holds sets keyed by role, user id and user
+	// id: the asked permissions of each role, the asked permissions granted
+	// to each user right now, and the dormitories of the scopes of each user.
+	GetPermissionSetsResponse struct {
+		RolePermissions map[string]map[string]bool
+		Grants          map[string]map[string]bool
+		Scopes          map[string]map[string]bool
+	}
+)
+
+type ListPermissionsResponse struct {
+	Permissions []Permission
+}
+
+type (
+	GetUserPermissionsRequest struct {
+		UserId string
+		Role   string
+	}
+
+	GetUserPermissionsResponse struct {
+		// RolePermissions come with the role of the user.
+		RolePermissions []string
+		Grants          []PermissionGrant
+	}
+)
+
+type (
+	GrantPermissionRequest struct {
+		UserId      string
+		DormitoryId string
+		Permission  string
+		GrantedBy   string
+		ValidFrom   *time.Time
+		ValidUntil  *time.Time
+	}
+
+	RevokePermissionRequest struct {
+		UserId      string
+		DormitoryId string
+		Permission  string
+	}
+)
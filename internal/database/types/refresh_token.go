@@ -0,0 +1,65 @@
+package dbtypes
+
+import "time"
+
+type RefreshToken struct {
+	Id        string
+	UserId    string
+	FamilyId  string
+	ClientId  string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+type (
+	CreateRefreshTokenRequest struct {
+		UserId    string
+		FamilyId  string
+		ClientId  string
+		TokenHash string
+		ExpiresAt time.Time
+		// Login records a user.logged_in event together with the token of
+		// a new session.
+		Login bool
+	}
+
+	CreateRefreshTokenResponse struct {
+		Id string
+	}
+)
+
+type (
+	GetRefreshTokenByHashRequest struct {
+		TokenHash string
+	}
+
+	GetRefreshTokenResponse struct {
+		Token *RefreshToken
+	}
+)
+
+type RotateRefreshTokenRequest struct {
+	UsedTokenId string
+	NewToken    *CreateRefreshTokenRequest
+}
+
+type RevokeRefreshTokenFamilyRequest struct {
+	FamilyId string
+}
+
+type RevokeUserRefreshTokensRequest struct {
+	UserId string
+}
+
+type (
+	IsSessionActiveRequest struct {
+		FamilyId string
+	}
+
+	IsSessionActiveResponse struct {
+		Active bool
+	}
+)
@@ -0,0 +1,36 @@
+package dbtypes
+
+import "time"
+
+const (
+	RoleChangeSourceAPI = "api"
+	RoleChangeSourceCLI = "cli"
+	// RoleChangeSourceGrant marks changes made by a time-bounded role grant
+	// taking effect or ending.
+	RoleChangeSourceGrant = "grant"
+)
+
+type RoleChange struct {
+	Id          string
+	UserId      string
+	DormitoryId string
+	OldRole     string
+	NewRole     string
+	ActorId     *string
+	ActorSource string
+	ChangedAt   time.Time
+}
+
+type (
+	ListRoleChangesRequest struct {
+		DormitoryId string
+		// UserId limits the history to one user when set.
+		UserId string
+		Limit  uint64
+		Offset uint64
+	}
+
+	ListRoleChangesResponse struct {
+		Changes []RoleChange
+	}
+)
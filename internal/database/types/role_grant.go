@@ -0,0 +1,65 @@
+package dbtypes
+
+import "time"
+
+// RoleGrant gives a user a role for [ValidFrom, ValidUntil). While it is in
+// effect the granted role replaces the role stored on the user.
+type RoleGrant struct {
+	Id          string
+	UserId      string
+	DormitoryId string
+	Role        string
+	ValidFrom   time.Time
+	ValidUntil  time.Time
+	GrantedBy   *string
+	CreatedAt   time.Time
+	StartedAt   *time.Time
+	ExpiredAt   *time.Time
+}
+
+type (
+	CreateRoleGrantRequest struct {
+		UserId      string
+		DormitoryId string
+		Role        string
+		ValidFrom   time.Time
+		ValidUntil  time.Time
+		GrantedBy   string
+	}
+
+	CreateRoleGrantResponse struct {
+		Grant *RoleGrant
+	}
+)
+
+type (
+	ListRoleGrantsRequest struct {
+		UserId      string
+		DormitoryId string
+	}
+
+	ListRoleGrantsResponse struct {
+		Grants []RoleGrant
+	}
+)
+
+// EndRoleGrantRequest moves the end of a grant to At if it ends later. The
+// sweeper then expires it as usual.
+type EndRoleGrantRequest struct {
+	Id          string
+	UserId      string
+	DormitoryId string
+	At          time.Time
+}
+
+type (
+	SweepGrantsRequest struct {
+		Now time.Time
+	}
+
+	SweepGrantsResponse struct {
+		StartedRoleGrants       []RoleGrant
+		ExpiredRoleGrants       []RoleGrant
+		ExpiredPermissionGrants []PermissionGrant
+	}
+)
@@ -0,0 +1,34 @@
+package dbtypes
+
+import "time"
+
+// DormitoryScope lets a user act in a dormitory other than their own.
+type DormitoryScope struct {
+	UserId      string
+	DormitoryId string
+	GrantedBy   *string
+	GrantedAt   time.Time
+}
+
+type (
+	GetUserDormitoryScopesRequest struct {
+		UserId string
+	}
+
+	GetUserDormitoryScopesResponse struct {
+		Scopes []DormitoryScope
+	}
+)
+
+type (
+	AddDormitoryScopeRequest struct {
+		UserId      string
+		DormitoryId string
+		GrantedBy   string
+	}
+
+	RemoveDormitoryScopeRequest struct {
+		UserId      string
+		DormitoryId string
+	}
+)
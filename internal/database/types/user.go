@@ -0,0 +1,77 @@
+package dbtypes
+
+import "time"
+
+const (
+	UserSortByCreatedAt = "created_at"
+	UserSortByEmail     = "email"
+	UserSortByRole      = "role"
+)
+
+type (
+	// ListUsersRequest lists users of one dormitory. Empty filters are not
+	// applied.
+	ListUsersRequest struct {
+		DormitoryId   string
+		Role          string
+		Status        string
+		EmailContains string
+		CreatedFrom   *time.Time
+		CreatedTo     *time.Time
+
+		SortBy   string
+		SortDesc bool
+		Limit    uint64
+		Offset   uint64
+	}
+
+	ListUsersResponse struct {
+		Users []User
+		Total uint64
+	}
+)
+
+type (
+	// UpdateUserRoleRequest changes the role of a user and records the change.
+	// ActorId is empty when the change does not come from a user.
+	UpdateUserRoleRequest struct {
+		UserId      string
+		DormitoryId string
+		Role        string
+		ActorId     string
+		ActorSource string
+	}
+
+	UpdateUserRoleResponse struct {
+		User *User
+	}
+)
+
+type (
+	// UpdateUserStatusRequest sets the status of a user and revokes all of
+	// their sessions.
+	UpdateUserStatusRequest struct {
+		UserId      string
+		DormitoryId string
+		Status      string
+		Reason      string
+		ExpiresAt   *time.Time
+	}
+
+	UpdateUserStatusResponse struct {
+		User *User
+	}
+)
+
+type (
+	// UpdateUserRequest applies a role change, a status change or both
+	// together.
+	UpdateUserRequest struct {
+		Role   *UpdateUserRoleRequest
+		Status *UpdateUserStatusRequest
+	}
+
+	UpdateUserResponse struct {
+		User *User
+	}
+)
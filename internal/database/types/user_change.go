@@ -0,0 +1,49 @@
+package dbtypes
+
+import "time"
+
+// Kinds of user changes.
+const (
+	// UserChangeKindRole is a change of the effective role, including role
+	// grants that start or end.
+	UserChangeKindRole   = "role"
+	UserChangeKindStatus = "status"
+	// UserChangeKindDormitory is a dormitory scope added to or removed from
+	// the user. The change belongs to the dormitory of the scope.
+	UserChangeKindDormitory = "dormitory"
+	// UserChangeKindSessionsRevoked is one or more sessions of the user
+	// revoked.
+	UserChangeKindSessionsRevoked = "sessions_revoked"
+	// UserChangeKindPermission is a permission granted to (NewValue) or
+	// revoked from (OldValue) the user, including grants that expire.
+	UserChangeKindPermission = "permission"
+)
+
+type UserChange struct {
+	Id          int64
+	UserId      string
+	DormitoryId string
+	Kind        string
+	OldValue    *string
+	NewValue    *string
+	CreatedAt   time.Time
+}
+
+type (
+	// ListUserChangesRequest lists changes with ids after AfterId, oldest
+	// first. An empty DormitoryId lists changes of every dormitory.
+	ListUserChangesRequest struct {
+		AfterId     int64
+		DormitoryId string
+		Limit       uint64
+	}
+
+	ListUserChangesResponse struct {
+		Changes []UserChange
+	}
+)
+
+type GetLastUserChangeIdResponse struct {
+	// Id is 0 when nothing was logged yet.
+	Id int64
+}
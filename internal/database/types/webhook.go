@@ -0,0 +1,177 @@
+package dbtypes
+
+import "time"
+
+// Statuses of webhook deliveries.
+const (
+	WebhookDeliveryStatusPending   = "pending"
+	WebhookDeliveryStatusDelivered = "delivered"
+	// WebhookDeliveryStatusDead is a delivery that ran out of attempts. It
+	// stays dead until an admin retries it.
+	WebhookDeliveryStatusDead = "dead"
+)
+
+type WebhookSubscription struct {
+	Id          string
+	DormitoryId string
+	URL         string
+	// Secret is stored as is, the deliveries are signed with it. A leaked
+	// secret is replaced by rotating it.
+	Secret string
+	// EventTypes is empty when the subscription receives every event.
+	EventTypes []string
+	Active     bool
+	CreatedBy  *string
+	CreatedAt  time.Time
+}
+
+type (
+	CreateWebhookSubscriptionRequest struct {
+		DormitoryId string
+		URL         string
+		Secret      string
+		EventTypes  []string
+		CreatedBy   string
+	}
+
+	CreateWebhookSubscriptionResponse struct {
+		Subscription *WebhookSubscription
+	}
+)
+
+type (
+	ListWebhookSubscriptionsRequest struct {
+		DormitoryId string
+	}
+
+	ListWebhookSubscriptionsResponse struct {
+		Subscriptions []WebhookSubscription
+	}
+)
+
+type (
+	// UpdateWebhookSubscriptionRequest changes the fields that are set. A nil
+	// EventTypes keeps the event types.
+	UpdateWebhookSubscriptionRequest struct {
+		Id          string
+		DormitoryId string
+		URL         *string
+		Secret      *string
+		EventTypes  []string
+		Active      *bool
+	}
+
+	UpdateWebhookSubscriptionResponse struct {
+		Subscription *WebhookSubscription
+	}
+)
+
+type DeleteWebhookSubscriptionRequest struct {
+	Id          string
+	DormitoryId string
+}
+
+// EnqueueWebhookDeliveriesRequest queues an event for every active
+// subscription of the dormitory that takes its type. An event queued before
+// is not queued again.
+type EnqueueWebhookDeliveriesRequest struct {
+	EventId     string
+	EventType   string
+	DormitoryId string
+	Body        []byte
+	Now         time.Time
+}
+
+type WebhookDelivery struct {
+	Id             string
+	SubscriptionId string
+	EventId        string
+	EventType      string
+	Body           []byte
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      *string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// ClaimedWebhookDelivery is a delivery together with the endpoint it goes to.
+type ClaimedWebhookDelivery struct {
+	WebhookDelivery
+	URL    string
+	Secret string
+}
+
+type (
+	// ClaimWebhookDeliveriesRequest leases up to Limit pending deliveries of
+	// active subscriptions that are due at Now, the same way outbox events
+	// are leased.
+	ClaimWebhookDeliveriesRequest struct {
+		Now   time.Time
+		Lease time.Duration
+		Limit uint64
+	}
+
+	ClaimWebhookDeliveriesResponse struct {
+		Deliveries []ClaimedWebhookDelivery
+	}
+)
+
+// RecordWebhookDeliveryAttemptRequest logs an attempt and moves the delivery
+// to Status. NextAttemptAt is used when the delivery stays pending.
+type RecordWebhookDeliveryAttemptRequest struct {
+	DeliveryId    string
+	StatusCode    *int
+	Error         string
+	Duration      time.Duration
+	AttemptedAt   time.Time
+	Status        string
+	NextAttemptAt time.Time
+}
+
+type (
+	// ListWebhookDeliveriesRequest lists the deliveries of a subscription,
+	// newest first. An empty Status lists deliveries in every status.
+	ListWebhookDeliveriesRequest struct {
+		SubscriptionId string
+		DormitoryId    string
+		Status         string
+		Limit          uint64
+		Offset         uint64
+	}
+
+	ListWebhookDeliveriesResponse struct {
+		Deliveries []WebhookDelivery
+	}
+)
+
+type WebhookDeliveryAttempt struct {
+	Id          int64
+	DeliveryId  string
+	StatusCode  *int
+	Error       *string
+	Duration    time.Duration
+	AttemptedAt time.Time
+}
+
+type (
+	ListWebhookDeliveryAttemptsRequest struct {
+		DeliveryId     string
+		SubscriptionId string
+		DormitoryId    string
+	}
+
+	ListWebhookDeliveryAttemptsResponse struct {
+		Attempts []WebhookDeliveryAttempt
+	}
+)
+
+// RetryWebhookDeliveryRequest makes a dead delivery pending again with a
+// fresh set of attempts.
+type RetryWebhookDeliveryRequest struct {
+	DeliveryId     string
+	SubscriptionId string
+	DormitoryId    string
+	Now            time.Time
+}
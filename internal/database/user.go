@@ -4,11 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/dormitory-life/auth/internal/constants"
 	dberrors "github.com/dormitory-life/auth/internal/database/errors"
 	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/lib/pq"
 )
 
 func (c *Database) GetUserById(
@@ -42,8 +44,11 @@ func (c *Database) getUserById(
 		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
 	)
 
+	// role is the effective role, taking role grants in effect into account
 	queryBuilder := psql.
-		Select("id", "dormitory_id", "role").
+		Select("id", "email", "dormitory_id").
+		Column(effectiveRoleColumn(time.Now().UTC())).
+		Columns("created_at", "status", "email_verified_at", "status_reason", "status_expires_at").
 		From(usersTable).
 		Where(squirrel.Eq{"id": request.Id}).
 		Limit(1)
@@ -53,12 +58,18 @@ func (c *Database) getUserById(
 		return nil, fmt.Errorf("%w: error building get user by id query: %v", dberrors.ErrInternal, err)
 	}
 
-	var user dbtypes.User
-	err = driver.QueryRowContext(ctx, query, args...).Scan(
+	var (
+		user      dbtypes.User
+		nullables nullableUserColumns
+	)
+	err = driver.QueryRowContext(ctx, query, args...).Scan(append([]any{
 		&user.UserId,
+		&user.Email,
 		&user.DormitoryId,
 		&user.Role,
-	)
+		&user.CreatedAt,
+		&user.Status,
+	}, nullables.dest()...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
@@ -67,10 +78,89 @@ func (c *Database) getUserById(
 		return nil, fmt.Errorf("%w: error executing get user by id query: %v", dberrors.ErrInternal, err)
 	}
 
+	nullables.apply(&user)
+
 	return &dbtypes.GetUserInfoByIdResponse{
 		UserId:      user.UserId,
+		Email:       user.Email,
 		DormitoryId: user.DormitoryId,
 		Role:        user.Role,
+		CreatedAt:   user.CreatedAt,
+
+		EmailVerifiedAt: user.EmailVerifiedAt,
+
+		Status:          user.Status,
+		StatusReason:    user.StatusReason,
+		StatusExpiresAt: user.StatusExpiresAt,
+	}, nil
+}
+
+func (c *Database) GetUsersByIds(
+	ctx context.Context,
+	request *dbtypes.GetUsersByIdsRequest,
+) (*dbtypes.GetUsersByIdsResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	if len(request.Ids) == 0 {
+		return &dbtypes.GetUsersByIdsResponse{}, nil
+	}
+
+	resp, err := c.getUsersByIds(ctx, c.db, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Database) getUsersByIds(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.GetUsersByIdsRequest,
+) (*dbtypes.GetUsersByIdsResponse, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	// columns in the order scanAdminUser reads them, role is the effective one
+	queryBuilder := psql.
+		Select("id", "email", "dormitory_id").
+		Column(effectiveRoleColumn(time.Now().UTC())).
+		Columns("created_at", "status", "email_verified_at", "status_reason", "status_expires_at").
+		From(usersTable).
+		Where("id = ANY(?)", pq.Array(request.Ids))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building get users by ids query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := driver.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing get users by ids query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	users := make([]dbtypes.User, 0, len(request.Ids))
+	for rows.Next() {
+		user, err := scanAdminUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning user: %v", dberrors.ErrInternal, err)
+		}
+
+		users = append(users, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating users: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.GetUsersByIdsResponse{
+		Users: users,
 	}, nil
 }
 
@@ -105,8 +195,11 @@ func (c *Database) getUserByEmail(
 		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
 	)
 
+	// role is the effective role, taking role grants in effect into account
 	queryBuilder := psql.
-		Select("id", "email", "password", "dormitory_id", "role", "created_at").
+		Select("id", "email", "password", "dormitory_id").
+		Column(effectiveRoleColumn(time.Now().UTC())).
+		Columns("created_at", "status", "email_verified_at", "status_reason", "status_expires_at").
 		From(usersTable).
 		Where(squirrel.Eq{"email": request.Email}).
 		Limit(1)
@@ -116,15 +209,19 @@ func (c *Database) getUserByEmail(
 		return nil, fmt.Errorf("%w: error building get user query: %v", dberrors.ErrInternal, err)
 	}
 
-	var user dbtypes.User
-	err = driver.QueryRowContext(ctx, query, args...).Scan(
+	var (
+		user      dbtypes.User
+		nullables nullableUserColumns
+	)
+	err = driver.QueryRowContext(ctx, query, args...).Scan(append([]any{
 		&user.UserId,
 		&user.Email,
 		&user.Password,
 		&user.DormitoryId,
 		&user.Role,
 		&user.CreatedAt,
-	)
+		&user.Status,
+	}, nullables.dest()...)...)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
@@ -133,6 +230,8 @@ func (c *Database) getUserByEmail(
 		return nil, fmt.Errorf("%w: error executing get user query: %v", dberrors.ErrInternal, err)
 	}
 
+	nullables.apply(&user)
+
 	return &dbtypes.GetUserResponse{
 		UserId:      user.UserId,
 		Email:       user.Email,
@@ -140,5 +239,144 @@ func (c *Database) getUserByEmail(
 		DormitoryId: user.DormitoryId,
 		Role:        user.Role,
 		CreatedAt:   user.CreatedAt,
+
+		EmailVerifiedAt: user.EmailVerifiedAt,
+
+		Status:          user.Status,
+		StatusReason:    user.StatusReason,
+		StatusExpiresAt: user.StatusExpiresAt,
 	}, nil
 }
+
+// GetUserPassword reads the password hash of the user. Only the password
+// checks need it, every other lookup leaves it out.
+func (c *Database) GetUserPassword(
+	ctx context.Context,
+	request *dbtypes.GetUserPasswordRequest,
+) (*dbtypes.GetUserPasswordResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	queryBuilder := psql.
+		Select("password").
+		From(usersTable).
+		Where(squirrel.Eq{"id": request.UserId})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building get user password query: %v", dberrors.ErrInternal, err)
+	}
+
+	var password string
+	err = c.db.QueryRowContext(ctx, query, args...).Scan(&password)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error executing get user password query: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.GetUserPasswordResponse{
+		Password: password,
+	}, nil
+}
+
+func (c *Database) UpdatePassword(
+	ctx context.Context,
+	request *dbtypes.UpdatePasswordRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		if err := c.updatePassword(ctx, tx, request.UserId, request.Password); err != nil {
+			return err
+		}
+
+		if !request.RevokeOtherSessions {
+			return nil
+		}
+
+		filter := squirrel.And{
+			squirrel.Eq{"user_id": request.UserId},
+			squirrel.NotEq{"family_id": request.KeepFamilyId},
+		}
+
+		return c.revokeRefreshTokens(ctx, tx, filter)
+	})
+}
+
+func (c *Database) updatePassword(
+	ctx context.Context,
+	driver Driver,
+	userId string,
+	password string,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		usersTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	queryBuilder := psql.Update(usersTable).
+		Set("password", password).
+		Where(squirrel.Eq{"id": userId})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building update password query: %v", dberrors.ErrInternal, err)
+	}
+
+	res, err := driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing update password query: %v", dberrors.ErrInternal, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: error getting affected rows: %v", dberrors.ErrInternal, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
+	}
+
+	return nil
+}
+
+// nullableUserColumns receives the nullable columns of users. They are
+// always selected last, in the order of dest.
+type nullableUserColumns struct {
+	emailVerifiedAt sql.NullTime
+	statusReason    sql.NullString
+	statusExpiresAt sql.NullTime
+}
+
+func (n *nullableUserColumns) dest() []any {
+	return []any{
+		&n.emailVerifiedAt,
+		&n.statusReason,
+		&n.statusExpiresAt,
+	}
+}
+
+func (n *nullableUserColumns) apply(user *dbtypes.User) {
+	if n.emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &n.emailVerifiedAt.Time
+	}
+
+	user.StatusReason = n.statusReason.String
+
+	if n.statusExpiresAt.Valid {
+		user.StatusExpiresAt = &n.statusExpiresAt.Time
+	}
+}
@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+// createUserChange appends a change of the user to the change log. An empty
+// DormitoryId is taken from the user.
+//
+// The advisory lock is held until the transaction ends, so a change that got
+// a smaller id is always committed before a change with a bigger one and
+// readers following the ids never skip a change committed late.
+func (c *Database) createUserChange(
+	ctx context.Context,
+	driver Driver,
+	change *dbtypes.UserChange,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		userChangesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserChangesTableName)
+		usersTable       = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UsersTableName)
+	)
+
+	// keeps the default placeholders, the insert numbers them
+	source := squirrel.
+		Select("u.id").
+		Column("COALESCE(?, u.dormitory_id)", nullableString(change.DormitoryId)).
+		Column("?", change.Kind).
+		Column("?::text", change.OldValue).
+		Column("?::text", change.NewValue).
+		From(usersTable + " u").
+		CrossJoin(fmt.Sprintf("pg_advisory_xact_lock(hashtext('%s'))", userChangesTable)).
+		Where(squirrel.Eq{"u.id": change.UserId})
+
+	queryBuilder := psql.Insert(userChangesTable).
+		Columns("user_id", "dormitory_id", "kind", "old_value", "new_value").
+		Select(source)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building create user change query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing create user change query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+func (c *Database) ListUserChanges(
+	ctx context.Context,
+	request *dbtypes.ListUserChangesRequest,
+) (*dbtypes.ListUserChangesResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		userChangesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserChangesTableName)
+	)
+
+	queryBuilder := psql.
+		Select("id", "user_id", "dormitory_id", "kind", "old_value", "new_value", "created_at").
+		From(userChangesTable).
+		Where(squirrel.Gt{"id": request.AfterId}).
+		OrderBy("id").
+		Limit(request.Limit)
+
+	if request.DormitoryId != "" {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"dormitory_id": request.DormitoryId})
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building list user changes query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing list user changes query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	changes := make([]dbtypes.UserChange, 0, request.Limit)
+	for rows.Next() {
+		var (
+			change   dbtypes.UserChange
+			oldValue sql.NullString
+			newValue sql.NullString
+		)
+
+		err := rows.Scan(
+			&change.Id, &change.UserId, &change.DormitoryId, &change.Kind,
+			&oldValue, &newValue, &change.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning user change: %v", dberrors.ErrInternal, err)
+		}
+
+		if oldValue.Valid {
+			change.OldValue = &oldValue.String
+		}
+
+		if newValue.Valid {
+			change.NewValue = &newValue.String
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating user changes: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.ListUserChangesResponse{
+		Changes: changes,
+	}, nil
+}
+
+func (c *Database) GetLastUserChangeId(ctx context.Context) (*dbtypes.GetLastUserChangeIdResponse, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		userChangesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.UserChangesTableName)
+	)
+
+	query, args, err := psql.
+		Select("COALESCE(MAX(id), 0)").
+		From(userChangesTable).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building get last user change query: %v", dberrors.ErrInternal, err)
+	}
+
+	var resp dbtypes.GetLastUserChangeIdResponse
+	err = c.db.QueryRowContext(ctx, query, args...).Scan(&resp.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing get last user change query: %v", dberrors.ErrInternal, err)
+	}
+
+	return &resp, nil
+}
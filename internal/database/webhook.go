@@ -0,0 +1,778 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/lib/pq"
+)
+
+var webhookSubscriptionColumns = []string{
+	"id", "dormitory_id", "url", "secret", "event_types", "active", "created_by", "created_at",
+}
+
+var webhookDeliveryColumns = []string{
+	"id", "subscription_id", "event_id", "event_type", "body", "status",
+	"attempts", "next_attempt_at", "last_error", "created_at", "delivered_at",
+}
+
+func (c *Database) CreateWebhookSubscription(
+	ctx context.Context,
+	request *dbtypes.CreateWebhookSubscriptionRequest,
+) (*dbtypes.CreateWebhookSubscriptionResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		subscriptionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookSubscriptionsTableName)
+	)
+
+	queryBuilder := psql.Insert(subscriptionsTable).
+		Columns("dormitory_id", "url", "secret", "event_types", "created_by").
+		Values(
+			request.DormitoryId, request.URL, request.Secret, pq.Array(eventTypes(request.EventTypes)),
+			nullableString(request.CreatedBy),
+		).
+		Suffix("RETURNING " + strings.Join(webhookSubscriptionColumns, ", "))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building create webhook subscription query: %v", dberrors.ErrInternal, err)
+	}
+
+	subscription, err := scanWebhookSubscription(c.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing create webhook subscription query: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.CreateWebhookSubscriptionResponse{
+		Subscription: subscription,
+	}, nil
+}
+
+func (c *Database) ListWebhookSubscriptions(
+	ctx context.Context,
+	request *dbtypes.ListWebhookSubscriptionsRequest,
+) (*dbtypes.ListWebhookSubscriptionsResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		subscriptionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookSubscriptionsTableName)
+	)
+
+	queryBuilder := psql.
+		Select(webhookSubscriptionColumns...).
+		From(subscriptionsTable).
+		Where(squirrel.Eq{"dormitory_id": request.DormitoryId}).
+		OrderBy("created_at", "id")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building list webhook subscriptions query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing list webhook subscriptions query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var subscriptions []dbtypes.WebhookSubscription
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning webhook subscription: %v", dberrors.ErrInternal, err)
+		}
+
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating webhook subscriptions: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.ListWebhookSubscriptionsResponse{
+		Subscriptions: subscriptions,
+	}, nil
+}
+
+func (c *Database) UpdateWebhookSubscription(
+	ctx context.Context,
+	request *dbtypes.UpdateWebhookSubscriptionRequest,
+) (*dbtypes.UpdateWebhookSubscriptionResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		subscriptionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookSubscriptionsTableName)
+	)
+
+	queryBuilder := psql.Update(subscriptionsTable).
+		Where(squirrel.Eq{
+			"id":           request.Id,
+			"dormitory_id": request.DormitoryId,
+		}).
+		Suffix("RETURNING " + strings.Join(webhookSubscriptionColumns, ", "))
+
+	if request.URL != nil {
+		queryBuilder = queryBuilder.Set("url", *request.URL)
+	}
+
+	if request.Secret != nil {
+		queryBuilder = queryBuilder.Set("secret", *request.Secret)
+	}
+
+	if request.EventTypes != nil {
+		queryBuilder = queryBuilder.Set("event_types", pq.Array(request.EventTypes))
+	}
+
+	if request.Active != nil {
+		queryBuilder = queryBuilder.Set("active", *request.Active)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building update webhook subscription query: %v", dberrors.ErrInternal, err)
+	}
+
+	subscription, err := scanWebhookSubscription(c.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: webhook not found", dberrors.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error executing update webhook subscription query: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.UpdateWebhookSubscriptionResponse{
+		Subscription: subscription,
+	}, nil
+}
+
+// DeleteWebhookSubscription deletes a subscription together with its
+// deliveries and their attempts.
+func (c *Database) DeleteWebhookSubscription(
+	ctx context.Context,
+	request *dbtypes.DeleteWebhookSubscriptionRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		subscriptionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookSubscriptionsTableName)
+	)
+
+	queryBuilder := psql.Delete(subscriptionsTable).
+		Where(squirrel.Eq{
+			"id":           request.Id,
+			"dormitory_id": request.DormitoryId,
+		})
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building delete webhook subscription query: %v", dberrors.ErrInternal, err)
+	}
+
+	res, err := c.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing delete webhook subscription query: %v", dberrors.ErrInternal, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: error getting affected rows: %v", dberrors.ErrInternal, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%w: webhook not found", dberrors.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (c *Database) EnqueueWebhookDeliveries(
+	ctx context.Context,
+	request *dbtypes.EnqueueWebhookDeliveriesRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		deliveriesTable    = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookDeliveriesTableName)
+		subscriptionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookSubscriptionsTableName)
+	)
+
+	// keeps the default placeholders, the insert numbers them
+	source := squirrel.
+		Select("s.id").
+		Column("?::uuid", request.EventId).
+		Column("?", request.EventType).
+		Column("?::text", string(request.Body)).
+		Column("?::timestamp", request.Now).
+		Column("?::timestamp", request.Now).
+		From(subscriptionsTable + " s").
+		Where(squirrel.Eq{
+			"s.dormitory_id": request.DormitoryId,
+			"s.active":       true,
+		}).
+		Where(squirrel.Or{
+			squirrel.Expr("cardinality(s.event_types) = 0"),
+			squirrel.Expr("?::text = ANY(s.event_types)", request.EventType),
+		})
+
+	queryBuilder := psql.Insert(deliveriesTable).
+		Columns("subscription_id", "event_id", "event_type", "body", "next_attempt_at", "created_at").
+		Select(source).
+		Suffix("ON CONFLICT (subscription_id, event_id) DO NOTHING")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building enqueue webhook deliveries query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = c.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing enqueue webhook deliveries query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+func (c *Database) ClaimWebhookDeliveries(
+	ctx context.Context,
+	request *dbtypes.ClaimWebhookDeliveriesRequest,
+) (*dbtypes.ClaimWebhookDeliveriesResponse, error) {
+	if request == nil || request.Limit == 0 {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		deliveriesTable    = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookDeliveriesTableName)
+		subscriptionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookSubscriptionsTableName)
+	)
+
+	// keeps the default placeholders, the update numbers them
+	due := squirrel.
+		Select("dd.id").
+		From(deliveriesTable+" dd").
+		Join(subscriptionsTable+" ds ON ds.id = dd.subscription_id").
+		Where(squirrel.Eq{
+			"dd.status": dbtypes.WebhookDeliveryStatusPending,
+			"ds.active": true,
+		}).
+		Where(squirrel.LtOrEq{"dd.next_attempt_at": request.Now}).
+		OrderBy("dd.next_attempt_at", "dd.id").
+		Limit(request.Limit).
+		Suffix("FOR UPDATE OF dd SKIP LOCKED")
+
+	returning := make([]string, 0, len(webhookDeliveryColumns)+2)
+	for _, column := range webhookDeliveryColumns {
+		returning = append(returning, "d."+column)
+	}
+
+	returning = append(returning, "s.url", "s.secret")
+
+	queryBuilder := psql.Update(deliveriesTable+" d").
+		Set("attempts", squirrel.Expr("d.attempts + 1")).
+		Set("next_attempt_at", request.Now.Add(request.Lease)).
+		From(subscriptionsTable + " s").
+		Where("s.id = d.subscription_id").
+		Where(squirrel.Expr("d.id IN (?)", due)).
+		Suffix("RETURNING " + strings.Join(returning, ", "))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building claim webhook deliveries query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing claim webhook deliveries query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]dbtypes.ClaimedWebhookDelivery, 0, request.Limit)
+	for rows.Next() {
+		var claimed dbtypes.ClaimedWebhookDelivery
+
+		delivery, err := scanWebhookDelivery(rows, &claimed.URL, &claimed.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning webhook delivery: %v", dberrors.ErrInternal, err)
+		}
+
+		claimed.WebhookDelivery = *delivery
+		deliveries = append(deliveries, claimed)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating webhook deliveries: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.ClaimWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+	}, nil
+}
+
+func (c *Database) RecordWebhookDeliveryAttempt(
+	ctx context.Context,
+	request *dbtypes.RecordWebhookDeliveryAttemptRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	return c.withTx(ctx, func(tx Driver) error {
+		if err := c.createWebhookDeliveryAttempt(ctx, tx, request); err != nil {
+			return err
+		}
+
+		return c.updateWebhookDelivery(ctx, tx, request)
+	})
+}
+
+func (c *Database) createWebhookDeliveryAttempt(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.RecordWebhookDeliveryAttemptRequest,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		attemptsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookDeliveryAttemptsTableName)
+	)
+
+	queryBuilder := psql.Insert(attemptsTable).
+		Columns("delivery_id", "status_code", "error", "duration_ms", "attempted_at").
+		Values(
+			request.DeliveryId, request.StatusCode, nullableString(request.Error),
+			request.Duration.Milliseconds(), request.AttemptedAt,
+		)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building create webhook delivery attempt query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing create webhook delivery attempt query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+func (c *Database) updateWebhookDelivery(
+	ctx context.Context,
+	driver Driver,
+	request *dbtypes.RecordWebhookDeliveryAttemptRequest,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		deliveriesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookDeliveriesTableName)
+	)
+
+	queryBuilder := psql.Update(deliveriesTable).
+		Set("status", request.Status).
+		Set("last_error", nullableString(request.Error)).
+		Where(squirrel.Eq{
+			"id":     request.DeliveryId,
+			"status": dbtypes.WebhookDeliveryStatusPending,
+		})
+
+	switch request.Status {
+	case dbtypes.WebhookDeliveryStatusDelivered:
+		queryBuilder = queryBuilder.Set("delivered_at", request.AttemptedAt)
+	case dbtypes.WebhookDeliveryStatusPending:
+		queryBuilder = queryBuilder.Set("next_attempt_at", request.NextAttemptAt)
+	}
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building update webhook delivery query: %v", dberrors.ErrInternal, err)
+	}
+
+	_, err = driver.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing update webhook delivery query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+func (c *Database) ListWebhookDeliveries(
+	ctx context.Context,
+	request *dbtypes.ListWebhookDeliveriesRequest,
+) (*dbtypes.ListWebhookDeliveriesResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	err := c.checkWebhookSubscription(ctx, c.db, request.DormitoryId, request.SubscriptionId)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		deliveriesTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookDeliveriesTableName)
+	)
+
+	filter := squirrel.Eq{"subscription_id": request.SubscriptionId}
+	if request.Status != "" {
+		filter["status"] = request.Status
+	}
+
+	queryBuilder := psql.
+		Select(webhookDeliveryColumns...).
+		From(deliveriesTable).
+		Where(filter).
+		OrderBy("created_at DESC", "id").
+		Limit(request.Limit).
+		Offset(request.Offset)
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building list webhook deliveries query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing list webhook deliveries query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]dbtypes.WebhookDelivery, 0, request.Limit)
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning webhook delivery: %v", dberrors.ErrInternal, err)
+		}
+
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating webhook deliveries: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.ListWebhookDeliveriesResponse{
+		Deliveries: deliveries,
+	}, nil
+}
+
+func (c *Database) ListWebhookDeliveryAttempts(
+	ctx context.Context,
+	request *dbtypes.ListWebhookDeliveryAttemptsRequest,
+) (*dbtypes.ListWebhookDeliveryAttemptsResponse, error) {
+	if request == nil {
+		return nil, dberrors.ErrBadRequest
+	}
+
+	_, err := c.getWebhookDeliveryStatus(ctx, c.db, request.DormitoryId, request.SubscriptionId, request.DeliveryId)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		attemptsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookDeliveryAttemptsTableName)
+	)
+
+	queryBuilder := psql.
+		Select("id", "delivery_id", "status_code", "error", "duration_ms", "attempted_at").
+		From(attemptsTable).
+		Where(squirrel.Eq{"delivery_id": request.DeliveryId}).
+		OrderBy("id")
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%w: error building list webhook delivery attempts query: %v", dberrors.ErrInternal, err)
+	}
+
+	rows, err := c.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error executing list webhook delivery attempts query: %v", dberrors.ErrInternal, err)
+	}
+	defer rows.Close()
+
+	var attempts []dbtypes.WebhookDeliveryAttempt
+	for rows.Next() {
+		var (
+			attempt    dbtypes.WebhookDeliveryAttempt
+			statusCode sql.NullInt64
+			errorText  sql.NullString
+			durationMs int64
+		)
+
+		err := rows.Scan(
+			&attempt.Id, &attempt.DeliveryId, &statusCode, &errorText, &durationMs, &attempt.AttemptedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error scanning webhook delivery attempt: %v", dberrors.ErrInternal, err)
+		}
+
+		if statusCode.Valid {
+			code := int(statusCode.Int64)
+			attempt.StatusCode = &code
+		}
+
+		if errorText.Valid {
+			attempt.Error = &errorText.String
+		}
+
+		attempt.Duration = time.Duration(durationMs) * time.Millisecond
+
+		attempts = append(attempts, attempt)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%w: error iterating webhook delivery attempts: %v", dberrors.ErrInternal, err)
+	}
+
+	return &dbtypes.ListWebhookDeliveryAttemptsResponse{
+		Attempts: attempts,
+	}, nil
+}
+
+// RetryWebhookDelivery makes a dead delivery pending again. Deliveries in
+// other statuses are left alone and reported as a conflict.
+func (c *Database) RetryWebhookDelivery(
+	ctx context.Context,
+	request *dbtypes.RetryWebhookDeliveryRequest,
+) error {
+	if request == nil {
+		return dberrors.ErrBadRequest
+	}
+
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		deliveriesTable    = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookDeliveriesTableName)
+		subscriptionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookSubscriptionsTableName)
+	)
+
+	// keeps the default placeholders, the update numbers them
+	subscription := squirrel.
+		Select("id").
+		From(subscriptionsTable).
+		Where(squirrel.Eq{
+			"id":           request.SubscriptionId,
+			"dormitory_id": request.DormitoryId,
+		})
+
+	queryBuilder := psql.Update(deliveriesTable).
+		Set("status", dbtypes.WebhookDeliveryStatusPending).
+		Set("attempts", 0).
+		Set("next_attempt_at", request.Now).
+		Where(squirrel.Eq{
+			"id":     request.DeliveryId,
+			"status": dbtypes.WebhookDeliveryStatusDead,
+		}).
+		Where(squirrel.Expr("subscription_id IN (?)", subscription))
+
+	query, args, err := queryBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building retry webhook delivery query: %v", dberrors.ErrInternal, err)
+	}
+
+	res, err := c.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("%w: error executing retry webhook delivery query: %v", dberrors.ErrInternal, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%w: error getting affected rows: %v", dberrors.ErrInternal, err)
+	}
+
+	if affected > 0 {
+		return nil
+	}
+
+	if _, err := c.getWebhookDeliveryStatus(ctx, c.db, request.DormitoryId, request.SubscriptionId, request.DeliveryId); err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: only dead deliveries can be retried", dberrors.ErrConflict)
+}
+
+// checkWebhookSubscription reports ErrNotFound unless the subscription
+// belongs to the dormitory.
+func (c *Database) checkWebhookSubscription(
+	ctx context.Context,
+	driver Driver,
+	dormitoryId string,
+	subscriptionId string,
+) error {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		subscriptionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookSubscriptionsTableName)
+	)
+
+	query, args, err := psql.
+		Select("1").
+		From(subscriptionsTable).
+		Where(squirrel.Eq{
+			"id":           subscriptionId,
+			"dormitory_id": dormitoryId,
+		}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("%w: error building check webhook subscription query: %v", dberrors.ErrInternal, err)
+	}
+
+	var exists int
+	err = driver.QueryRowContext(ctx, query, args...).Scan(&exists)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("%w: webhook not found", dberrors.ErrNotFound)
+		}
+
+		return fmt.Errorf("%w: error executing check webhook subscription query: %v", dberrors.ErrInternal, err)
+	}
+
+	return nil
+}
+
+// getWebhookDeliveryStatus returns the status of a delivery of a
+// subscription of the dormitory.
+func (c *Database) getWebhookDeliveryStatus(
+	ctx context.Context,
+	driver Driver,
+	dormitoryId string,
+	subscriptionId string,
+	deliveryId string,
+) (string, error) {
+	var (
+		psql = squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+		deliveriesTable    = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookDeliveriesTableName)
+		subscriptionsTable = fmt.Sprintf("%s.%s", constants.SchemaName, constants.WebhookSubscriptionsTableName)
+	)
+
+	query, args, err := psql.
+		Select("d.status").
+		From(deliveriesTable + " d").
+		Join(subscriptionsTable + " s ON s.id = d.subscription_id").
+		Where(squirrel.Eq{
+			"d.id":           deliveryId,
+			"s.id":           subscriptionId,
+			"s.dormitory_id": dormitoryId,
+		}).
+		ToSql()
+	if err != nil {
+		return "", fmt.Errorf("%w: error building get webhook delivery query: %v", dberrors.ErrInternal, err)
+	}
+
+	var status string
+	err = driver.QueryRowContext(ctx, query, args...).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", fmt.Errorf("%w: webhook delivery not found", dberrors.ErrNotFound)
+		}
+
+		return "", fmt.Errorf("%w: error executing get webhook delivery query: %v", dberrors.ErrInternal, err)
+	}
+
+	return status, nil
+}
+
+func scanWebhookSubscription(row rowScanner) (*dbtypes.WebhookSubscription, error) {
+	var (
+		subscription dbtypes.WebhookSubscription
+		createdBy    sql.NullString
+	)
+
+	err := row.Scan(
+		&subscription.Id,
+		&subscription.DormitoryId,
+		&subscription.URL,
+		&subscription.Secret,
+		pq.Array(&subscription.EventTypes),
+		&subscription.Active,
+		&createdBy,
+		&subscription.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if createdBy.Valid {
+		subscription.CreatedBy = &createdBy.String
+	}
+
+	return &subscription, nil
+}
+
+func scanWebhookDelivery(row rowScanner, extra ...any) (*dbtypes.WebhookDelivery, error) {
+	var (
+		delivery    dbtypes.WebhookDelivery
+		lastError   sql.NullString
+		deliveredAt sql.NullTime
+	)
+
+	err := row.Scan(append([]any{
+		&delivery.Id,
+		&delivery.SubscriptionId,
+		&delivery.EventId,
+		&delivery.EventType,
+		&delivery.Body,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&lastError,
+		&delivery.CreatedAt,
+		&deliveredAt,
+	}, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	if lastError.Valid {
+		delivery.LastError = &lastError.String
+	}
+
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &delivery, nil
+}
+
+// eventTypes keeps an empty list of event types from being stored as NULL.
+func eventTypes(types []string) []string {
+	if types == nil {
+		return []string{}
+	}
+
+	return types
+}
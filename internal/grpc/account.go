@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	"log/slog"
+
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+	pb "github.com/dormitory-life/auth/proto"
+)
+
+// credentialMethods carry passwords or tokens, their messages are not
+// logged.
+var credentialMethods = map[string]bool{
+	pb.AuthProtoService_ValidateToken_FullMethodName: true,
+	pb.AuthProtoService_Register_FullMethodName:      true,
+	pb.AuthProtoService_Login_FullMethodName:         true,
+	pb.AuthProtoService_RefreshTokens_FullMethodName: true,
+	pb.AuthProtoService_Logout_FullMethodName:        true,
+}
+
+func (s *GRPCServer) Register(
+	ctx context.Context,
+	req *pb.RegisterRequest,
+) (*pb.RegisterResponse, error) {
+	s.logger.Debug("gRPC Register called",
+		slog.String("dormitory_id", req.GetDormitoryId()),
+		slog.String("client_id", req.GetClientId()))
+
+	res, err := s.authService.Register(ctx, &rmodel.RegisterRequest{
+		Email:       req.GetEmail(),
+		Password:    req.GetPassword(),
+		DormitoryId: req.GetDormitoryId(),
+		ClientId:    req.GetClientId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RegisterResponse{
+		UserId:                    res.UserId,
+		DormitoryId:               res.DormitoryId,
+		AccessToken:               res.AccessToken,
+		RefreshToken:              res.RefreshToken,
+		EmailVerificationRequired: res.EmailVerificationRequired,
+	}, nil
+}
+
+func (s *GRPCServer) Login(
+	ctx context.Context,
+	req *pb.LoginRequest,
+) (*pb.LoginResponse, error) {
+	s.logger.Debug("gRPC Login called",
+		slog.String("client_id", req.GetClientId()))
+
+	res, err := s.authService.Login(ctx, &rmodel.LoginRequest{
+		Email:    req.GetEmail(),
+		Password: req.GetPassword(),
+		ClientId: req.GetClientId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LoginResponse{
+		UserId:       res.UserId,
+		DormitoryId:  res.DormitoryId,
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+	}, nil
+}
+
+func (s *GRPCServer) RefreshTokens(
+	ctx context.Context,
+	req *pb.RefreshTokensRequest,
+) (*pb.RefreshTokensResponse, error) {
+	s.logger.Debug("gRPC RefreshTokens called")
+
+	res, err := s.authService.RefreshTokens(ctx, &rmodel.RefreshTokensRequest{
+		AccessToken:  req.GetAccessToken(),
+		RefreshToken: req.GetRefreshToken(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.RefreshTokensResponse{
+		AccessToken:  res.AccessToken,
+		RefreshToken: res.RefreshToken,
+	}, nil
+}
+
+func (s *GRPCServer) Logout(
+	ctx context.Context,
+	req *pb.LogoutRequest,
+) (*pb.LogoutResponse, error) {
+	s.logger.Debug("gRPC Logout called")
+
+	err := s.authService.Logout(ctx, &rmodel.LogoutRequest{
+		RefreshToken: req.GetRefreshToken(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.LogoutResponse{}, nil
+}
+
+func (s *GRPCServer) GetUser(
+	ctx context.Context,
+	req *pb.GetUserRequest,
+) (*pb.GetUserResponse, error) {
+	s.logger.Debug("gRPC GetUser called",
+		slog.String("user_id", req.GetUserId()))
+
+	res, err := s.authService.GetUserInfoById(ctx, &rmodel.GetUserByIdRequest{
+		UserId: req.GetUserId(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetUserResponse{
+		UserId:      res.Info.UserId,
+		DormitoryId: res.Info.DormitoryId,
+		Role:        res.Info.Role,
+		Status:      res.Info.Status,
+	}, nil
+}
@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	auth "github.com/dormitory-life/auth/internal/service"
+	pb "github.com/dormitory-life/auth/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain is the ErrorInfo domain of errors returned by the service.
+const errorDomain = "auth.dormitory-life"
+
+// serviceErrors maps service errors to the gRPC code and the ErrorInfo reason
+// callers can branch on.
+var serviceErrors = []struct {
+	err    error
+	code   codes.Code
+	reason string
+}{
+	{auth.ErrBadRequest, codes.InvalidArgument, "BAD_REQUEST"},
+	{auth.ErrNotFound, codes.NotFound, "NOT_FOUND"},
+	{auth.ErrConflict, codes.FailedPrecondition, "CONFLICT"},
+	{auth.ErrForbidden, codes.PermissionDenied, "FORBIDDEN"},
+	{auth.ErrUnauthorized, codes.Unauthenticated, "UNAUTHORIZED"},
+	{auth.ErrInternal, codes.Internal, "INTERNAL"},
+}
+
+var denialReasons = map[string]pb.DenialReason{
+	constants.DenialReasonUserNotFound:         pb.DenialReason_DENIAL_REASON_USER_NOT_FOUND,
+	constants.DenialReasonOtherDormitory:       pb.DenialReason_DENIAL_REASON_OTHER_DORMITORY,
+	constants.DenialReasonAccountInactive:      pb.DenialReason_DENIAL_REASON_ACCOUNT_INACTIVE,
+	constants.DenialReasonPermissionNotGranted: pb.DenialReason_DENIAL_REASON_PERMISSION_NOT_GRANTED,
+}
+
+// errorInterceptor turns errors of the handlers into gRPC statuses, so
+// callers get a code and an ErrorInfo instead of an Unknown status with the
+// error text. Details of internal errors are only logged.
+func (s *GRPCServer) errorInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, s.statusError(info.FullMethod, err)
+	}
+
+	return resp, nil
+}
+
+func (s *GRPCServer) errorStreamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := handler(srv, stream); err != nil {
+		return s.statusError(info.FullMethod, err)
+	}
+
+	return nil
+}
+
+func (s *GRPCServer) statusError(method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+
+	code, reason := codes.Internal, "INTERNAL"
+	for _, serviceError := range serviceErrors {
+		if errors.Is(err, serviceError.err) {
+			code, reason = serviceError.code, serviceError.reason
+			break
+		}
+	}
+
+	message := err.Error()
+	if code == codes.Internal {
+		s.logger.Error("gRPC internal error",
+			slog.String("method", method),
+			slog.String("error", message))
+
+		message = auth.ErrInternal.Error()
+	}
+
+	st, detailsErr := status.New(code, message).WithDetails(&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: errorDomain,
+	})
+	if detailsErr != nil {
+		return status.Error(code, message)
+	}
+
+	return st.Err()
+}
+
+// denialReason converts the denial reason of the service, empty when access
+// is allowed.
+func denialReason(reason string) pb.DenialReason {
+	return denialReasons[reason]
+}
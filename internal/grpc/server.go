@@ -2,7 +2,6 @@ package grpc
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log/slog"
 	"net"
@@ -39,7 +38,9 @@ func NewServer(cfg GRPCServerConfig) *GRPCServer {
 
 func (s *GRPCServer) Start() error {
 	s.grpcServer = grpc.NewServer(
-		grpc.UnaryInterceptor(s.loggingInterceptor),
+		// errors are translated last, so the log keeps the original error
+		grpc.ChainUnaryInterceptor(s.errorInterceptor, s.loggingInterceptor),
+		grpc.ChainStreamInterceptor(s.errorStreamInterceptor, s.loggingStreamInterceptor),
 	)
 
 	pb.RegisterAuthProtoServiceServer(s.grpcServer, s)
@@ -73,9 +74,14 @@ func (s *GRPCServer) loggingInterceptor(
 	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
-	s.logger.Debug("gRPC request",
-		slog.String("method", info.FullMethod),
-		slog.Any("request", req))
+	if credentialMethods[info.FullMethod] {
+		s.logger.Debug("gRPC request",
+			slog.String("method", info.FullMethod))
+	} else {
+		s.logger.Debug("gRPC request",
+			slog.String("method", info.FullMethod),
+			slog.Any("request", req))
+	}
 
 	resp, err := handler(ctx, req)
 
@@ -83,7 +89,7 @@ func (s *GRPCServer) loggingInterceptor(
 		s.logger.Error("gRPC error",
 			slog.String("method", info.FullMethod),
 			slog.String("error", err.Error()))
-	} else {
+	} else if !credentialMethods[info.FullMethod] {
 		s.logger.Debug("gRPC response",
 			slog.String("method", info.FullMethod),
 			slog.Any("response", resp))
@@ -92,71 +98,152 @@ func (s *GRPCServer) loggingInterceptor(
 	return resp, err
 }
 
+func (s *GRPCServer) loggingStreamInterceptor(
+	srv interface{},
+	stream grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	s.logger.Debug("gRPC stream opened",
+		slog.String("method", info.FullMethod))
+
+	err := handler(srv, stream)
+
+	// a watcher going away ends its stream with the canceled context
+	if err != nil && stream.Context().Err() == nil {
+		s.logger.Error("gRPC stream error",
+			slog.String("method", info.FullMethod),
+			slog.String("error", err.Error()))
+	} else {
+		s.logger.Debug("gRPC stream closed",
+			slog.String("method", info.FullMethod))
+	}
+
+	return err
+}
+
+// CheckAccess is kept for services that predate permissions: role_required
+// asks for the admin permission, otherwise access to the dormitory is enough.
 func (s *GRPCServer) CheckAccess(
 	ctx context.Context,
 	req *pb.CheckAccessRequest,
 ) (*pb.CheckAccessResponse, error) {
-	switch req.RoleRequired {
-	case true:
-		s.logger.Debug("gRPC CheckAccess for admin called",
-			slog.String("user_id", req.GetUserId()),
-			slog.String("dormitory_id", req.GetDormitoryId()),
-			slog.Any("roleRequired", req.RoleRequired))
-	case false:
-		s.logger.Debug("gRPC CheckAccess for student called",
-			slog.String("user_id", req.GetUserId()),
-			slog.String("dormitory_id", req.GetDormitoryId()),
-			slog.Any("roleRequired", req.RoleRequired))
+	s.logger.Debug("gRPC CheckAccess called",
+		slog.String("user_id", req.GetUserId()),
+		slog.String("dormitory_id", req.GetDormitoryId()),
+		slog.Bool("role_required", req.GetRoleRequired()))
+
+	res, err := s.authService.CheckPermission(ctx, &rmodel.CheckPermissionRequest{
+		UserId:      req.GetUserId(),
+		DormitoryId: req.GetDormitoryId(),
+		Permission:  accessPermission(req),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	res, err := s.authService.GetUserInfoById(ctx, &rmodel.GetUserByIdRequest{
-		UserId: req.UserId,
+	return &pb.CheckAccessResponse{
+		Allowed:      res.Allowed,
+		Reason:       res.Reason,
+		UserRole:     res.UserRole,
+		DenialReason: denialReason(res.DenialReason),
+	}, nil
+}
+
+// BatchCheckAccess makes many CheckAccess checks in one call. Results are in
+// the order of the checks.
+func (s *GRPCServer) BatchCheckAccess(
+	ctx context.Context,
+	req *pb.BatchCheckAccessRequest,
+) (*pb.BatchCheckAccessResponse, error) {
+	s.logger.Debug("gRPC BatchCheckAccess called",
+		slog.Int("checks", len(req.GetChecks())))
+
+	checks := make([]rmodel.CheckPermissionRequest, 0, len(req.GetChecks()))
+	for _, check := range req.GetChecks() {
+		checks = append(checks, rmodel.CheckPermissionRequest{
+			UserId:      check.GetUserId(),
+			DormitoryId: check.GetDormitoryId(),
+			Permission:  accessPermission(check),
+		})
+	}
+
+	res, err := s.authService.BatchCheckPermission(ctx, &rmodel.BatchCheckPermissionRequest{
+		Checks: checks,
 	})
 	if err != nil {
-		if errors.Is(err, auth.ErrNotFound) {
-			return &pb.CheckAccessResponse{
-				Allowed:  false,
-				Reason:   "",
-				UserRole: auth.ErrNotFound.Error(),
-			}, err
-		}
-
-		return &pb.CheckAccessResponse{
-			Allowed:  false,
-			Reason:   "",
-			UserRole: err.Error(),
-		}, err
+		return nil, err
 	}
 
-	result, err := s.checkFieldsEquality(req, res)
+	results := make([]*pb.CheckAccessResponse, 0, len(res.Results))
+	for _, result := range res.Results {
+		results = append(results, &pb.CheckAccessResponse{
+			Allowed:      result.Allowed,
+			Reason:       result.Reason,
+			UserRole:     result.UserRole,
+			DenialReason: denialReason(result.DenialReason),
+		})
+	}
 
-	return result, err
+	return &pb.BatchCheckAccessResponse{
+		Results: results,
+	}, nil
 }
 
-func (s *GRPCServer) checkFieldsEquality(
-	req *pb.CheckAccessRequest,
-	resp *rmodel.GetUserByIdResponse,
-) (*pb.CheckAccessResponse, error) {
-	s.logger.Debug("Checking equality: dormitory ids compare", slog.String("requested", req.DormitoryId), slog.String("target", resp.Info.DormitoryId))
-	if req.DormitoryId != resp.Info.DormitoryId {
-		return &pb.CheckAccessResponse{
-			Allowed:  false,
-			Reason:   "Permission for another dormitory is denied",
-			UserRole: resp.Info.Role,
-		}, nil
+// accessPermission is the permission a CheckAccess check stands for.
+func accessPermission(req *pb.CheckAccessRequest) string {
+	if req.GetRoleRequired() {
+		return constants.PermissionDormitoryAdmin
+	}
+
+	return constants.PermissionDormitoryAccess
+}
+
+func (s *GRPCServer) CheckPermission(
+	ctx context.Context,
+	req *pb.CheckPermissionRequest,
+) (*pb.CheckPermissionResponse, error) {
+	s.logger.Debug("gRPC CheckPermission called",
+		slog.String("user_id", req.GetUserId()),
+		slog.String("dormitory_id", req.GetDormitoryId()),
+		slog.String("permission", req.GetPermission()))
+
+	res, err := s.authService.CheckPermission(ctx, &rmodel.CheckPermissionRequest{
+		UserId:      req.GetUserId(),
+		DormitoryId: req.GetDormitoryId(),
+		Permission:  req.GetPermission(),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if req.RoleRequired && resp.Info.Role != constants.UserAdminRole {
-		return &pb.CheckAccessResponse{
-			Allowed:  false,
-			Reason:   fmt.Sprintf("Permission for student is deniedUser role is '%s', required 'admin'", resp.Info.Role),
-			UserRole: resp.Info.Role,
-		}, nil
+	return &pb.CheckPermissionResponse{
+		Allowed:      res.Allowed,
+		Reason:       res.Reason,
+		UserRole:     res.UserRole,
+		DenialReason: denialReason(res.DenialReason),
+	}, nil
+}
+
+func (s *GRPCServer) ValidateToken(
+	ctx context.Context,
+	req *pb.ValidateTokenRequest,
+) (*pb.ValidateTokenResponse, error) {
+	s.logger.Debug("gRPC ValidateToken called")
+
+	res, err := s.authService.IntrospectToken(ctx, &rmodel.IntrospectRequest{
+		Token: req.GetToken(),
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &pb.CheckAccessResponse{
-		Allowed:  true,
-		Reason:   "Allowed",
-		UserRole: resp.Info.Role,
+	return &pb.ValidateTokenResponse{
+		Active:      res.Active,
+		UserId:      res.UserId,
+		DormitoryId: res.DormitoryId,
+		Role:        res.Role,
+		Exp:         res.ExpiresAt,
+		Dormitories: res.Dormitories,
 	}, nil
 }
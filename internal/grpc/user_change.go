@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"log/slog"
+
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+	pb "github.com/dormitory-life/auth/proto"
+	"google.golang.org/grpc"
+)
+
+var userChangeKinds = map[string]pb.UserChangeKind{
+	dbtypes.UserChangeKindRole:            pb.UserChangeKind_USER_CHANGE_KIND_ROLE,
+	dbtypes.UserChangeKindStatus:          pb.UserChangeKind_USER_CHANGE_KIND_STATUS,
+	dbtypes.UserChangeKindDormitory:       pb.UserChangeKind_USER_CHANGE_KIND_DORMITORY,
+	dbtypes.UserChangeKindSessionsRevoked: pb.UserChangeKind_USER_CHANGE_KIND_SESSIONS_REVOKED,
+	dbtypes.UserChangeKindPermission:      pb.UserChangeKind_USER_CHANGE_KIND_PERMISSION,
+}
+
+// WatchUserChanges streams changes of users until the client goes away.
+// Clients resume with the cursor of the last event they received.
+func (s *GRPCServer) WatchUserChanges(
+	req *pb.WatchUserChangesRequest,
+	stream grpc.ServerStreamingServer[pb.UserChangeEvent],
+) error {
+	s.logger.Debug("gRPC WatchUserChanges called",
+		slog.String("cursor", req.GetCursor()),
+		slog.String("dormitory_id", req.GetDormitoryId()))
+
+	return s.authService.WatchUserChanges(stream.Context(), &rmodel.WatchUserChangesRequest{
+		Cursor:      req.GetCursor(),
+		DormitoryId: req.GetDormitoryId(),
+	}, func(change *rmodel.UserChange) error {
+		return stream.Send(&pb.UserChangeEvent{
+			Cursor:      change.Cursor,
+			UserId:      change.UserId,
+			DormitoryId: change.DormitoryId,
+			Kind:        userChangeKinds[change.Kind],
+			OldValue:    change.OldValue,
+			NewValue:    change.NewValue,
+			CreatedAt:   change.CreatedAt.Unix(),
+		})
+	})
+}
@@ -0,0 +1,231 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgorithmHS256 = "HS256"
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+
+	minRSAKeyBits = 2048
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrInvalidKey           = errors.New("invalid signing key")
+)
+
+type Key struct {
+	Id         string
+	Method     jwt.SigningMethod
+	SigningKey any
+	VerifyKey  any
+	// NotAfter is set for retired keys: tokens signed by the key are
+	// rejected after this moment. Zero means no limit.
+	NotAfter time.Time
+}
+
+func LoadKey(cfg config.JWTKeyConfig) (*Key, error) {
+	algorithm := cfg.Algorithm
+	if algorithm == "" {
+		algorithm = AlgorithmHS256
+	}
+
+	if algorithm == AlgorithmHS256 {
+		if cfg.Secret == "" {
+			return nil, fmt.Errorf("%w: secret is required for %s", ErrInvalidKey, algorithm)
+		}
+
+		return &Key{
+			Id:         cfg.Id,
+			Method:     jwt.SigningMethodHS256,
+			SigningKey: []byte(cfg.Secret),
+			VerifyKey:  []byte(cfg.Secret),
+			NotAfter:   cfg.NotAfter,
+		}, nil
+	}
+
+	key, err := loadAsymmetricKey(algorithm, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	key.Id = cfg.Id
+	key.NotAfter = cfg.NotAfter
+
+	if key.Id == "" {
+		key.Id, err = Thumbprint(key.VerifyKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+func loadAsymmetricKey(algorithm string, cfg config.JWTKeyConfig) (*Key, error) {
+	if cfg.PrivateKeyPath != "" {
+		privateKey, err := readPrivateKey(cfg.PrivateKeyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := newAsymmetricKey(algorithm, privateKey.Public())
+		if err != nil {
+			return nil, err
+		}
+
+		key.SigningKey = privateKey
+
+		return key, nil
+	}
+
+	if cfg.PublicKeyPath != "" {
+		publicKey, err := readPublicKey(cfg.PublicKeyPath)
+		if err != nil {
+			return nil, err
+		}
+
+		return newAsymmetricKey(algorithm, publicKey)
+	}
+
+	return nil, fmt.Errorf("%w: private_key_path or public_key_path is required for %s", ErrInvalidKey, algorithm)
+}
+
+func (k *Key) IsSymmetric() bool {
+	_, ok := k.VerifyKey.([]byte)
+	return ok
+}
+
+func (k *Key) CanSign() bool {
+	return k.SigningKey != nil
+}
+
+func (k *Key) Expired(now time.Time) bool {
+	return !k.NotAfter.IsZero() && now.After(k.NotAfter)
+}
+
+func newAsymmetricKey(algorithm string, publicKey crypto.PublicKey) (*Key, error) {
+	switch algorithm {
+	case AlgorithmRS256:
+		rsaKey, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s requires an RSA key", ErrInvalidKey, algorithm)
+		}
+
+		if rsaKey.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("%w: RSA key must be at least %d bits", ErrInvalidKey, minRSAKeyBits)
+		}
+
+		return &Key{
+			Method:    jwt.SigningMethodRS256,
+			VerifyKey: rsaKey,
+		}, nil
+
+	case AlgorithmES256:
+		ecKey, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%w: %s requires a P-256 EC key", ErrInvalidKey, algorithm)
+		}
+
+		return &Key{
+			Method:    jwt.SigningMethodES256,
+			VerifyKey: ecKey,
+		}, nil
+
+	case AlgorithmEdDSA:
+		edKey, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s requires an Ed25519 key", ErrInvalidKey, algorithm)
+		}
+
+		return &Key{
+			Method:    jwt.SigningMethodEdDSA,
+			VerifyKey: edKey,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedAlgorithm, algorithm)
+	}
+}
+
+func readPrivateKey(path string) (crypto.Signer, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key any
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: unsupported PEM block %q", ErrInvalidKey, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("%w: key in %s cannot sign", ErrInvalidKey, path)
+	}
+
+	return signer, nil
+}
+
+func readPublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEMBlock(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key any
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: unsupported PEM block %q", ErrInvalidKey, block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+
+	return key, nil
+}
+
+func readPEMBlock(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found in %s", ErrInvalidKey, path)
+	}
+
+	return block, nil
+}
@@ -0,0 +1,139 @@
+package jwtkeys
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/config"
+)
+
+var (
+	ErrUnknownKey = errors.New("unknown signing key")
+	ErrKeyExpired = errors.New("signing key expired")
+)
+
+// Keyring holds the active signing key together with keys that are only
+// used to verify tokens issued before the last rotation.
+type Keyring struct {
+	mu     sync.RWMutex
+	active *Key
+	keys   map[string]*Key
+}
+
+func LoadKeyring(cfg config.JWTConfig) (*Keyring, error) {
+	keyConfigs := cfg.Keys
+	if len(keyConfigs) == 0 {
+		keyConfigs = []config.JWTKeyConfig{{
+			Id:             cfg.KeyId,
+			Algorithm:      cfg.Algorithm,
+			Secret:         cfg.Secret,
+			PrivateKeyPath: cfg.PrivateKeyPath,
+			Active:         true,
+		}}
+	}
+
+	keyring := &Keyring{
+		keys: make(map[string]*Key, len(keyConfigs)),
+	}
+
+	for _, keyConfig := range keyConfigs {
+		key, err := LoadKey(keyConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key %q: %w", keyConfig.Id, err)
+		}
+
+		if _, ok := keyring.keys[key.Id]; ok {
+			return nil, fmt.Errorf("%w: duplicate key id %q", ErrInvalidKey, key.Id)
+		}
+
+		if keyConfig.Active {
+			if keyring.active != nil {
+				return nil, fmt.Errorf("%w: more than one active key", ErrInvalidKey)
+			}
+
+			if !key.CanSign() {
+				return nil, fmt.Errorf("%w: active key %q has no private key", ErrInvalidKey, key.Id)
+			}
+
+			if !key.NotAfter.IsZero() {
+				return nil, fmt.Errorf("%w: active key %q must not have not_after", ErrInvalidKey, key.Id)
+			}
+
+			keyring.active = key
+		}
+
+		keyring.keys[key.Id] = key
+	}
+
+	if keyring.active == nil {
+		return nil, fmt.Errorf("%w: no active key", ErrInvalidKey)
+	}
+
+	return keyring, nil
+}
+
+func (r *Keyring) Active() *Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.active
+}
+
+// Lookup returns the key that must be used to verify a token carrying kid.
+func (r *Keyring) Lookup(kid string, now time.Time) (*Key, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	key, ok := r.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+
+	if key.Expired(now) {
+		return nil, fmt.Errorf("%w: %q", ErrKeyExpired, kid)
+	}
+
+	return key, nil
+}
+
+// JWKS returns the public keys of every asymmetric key that can still be
+// used for verification.
+func (r *Keyring) JWKS(now time.Time) JWKSet {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keySet := JWKSet{
+		Keys: []JWK{},
+	}
+
+	if jwk, ok := r.active.PublicJWK(); ok {
+		keySet.Keys = append(keySet.Keys, jwk)
+	}
+
+	for _, key := range r.keys {
+		if key == r.active || key.Expired(now) {
+			continue
+		}
+
+		if jwk, ok := key.PublicJWK(); ok {
+			keySet.Keys = append(keySet.Keys, jwk)
+		}
+	}
+
+	return keySet
+}
+
+// Replace swaps the keys with the ones from other, used on config reload.
+func (r *Keyring) Replace(other *Keyring) {
+	other.mu.RLock()
+	active, keys := other.active, other.keys
+	other.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.active = active
+	r.keys = keys
+}
@@ -0,0 +1,60 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileNotifier appends every message as a JSON line to a file.
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) (*FileNotifier, error) {
+	if path == "" {
+		return nil, errors.New("file notifier requires file_path")
+	}
+
+	return &FileNotifier{
+		path: path,
+	}, nil
+}
+
+func (n *FileNotifier) SendPasswordReset(ctx context.Context, msg *PasswordResetMessage) error {
+	return n.write("password_reset", msg)
+}
+
+func (n *FileNotifier) SendEmailVerification(ctx context.Context, msg *EmailVerificationMessage) error {
+	return n.write("email_verification", msg)
+}
+
+func (n *FileNotifier) write(kind string, msg any) error {
+	line, err := json.Marshal(struct {
+		Kind    string `json:"kind"`
+		Message any    `json:"message"`
+	}{kind, msg})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	file, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open notifications file: %w", err)
+	}
+
+	defer file.Close()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+
+	return nil
+}
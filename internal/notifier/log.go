@@ -0,0 +1,38 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogNotifier writes messages to the service log. It is meant for local
+// development only.
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{
+		logger: logger,
+	}
+}
+
+func (n *LogNotifier) SendPasswordReset(ctx context.Context, msg *PasswordResetMessage) error {
+	n.logger.Info("password reset requested",
+		slog.String("email", msg.Email),
+		slog.String("token", msg.Token),
+		slog.Time("expires_at", msg.ExpiresAt),
+	)
+
+	return nil
+}
+
+func (n *LogNotifier) SendEmailVerification(ctx context.Context, msg *EmailVerificationMessage) error {
+	n.logger.Info("email verification requested",
+		slog.String("email", msg.Email),
+		slog.String("token", msg.Token),
+		slog.Time("expires_at", msg.ExpiresAt),
+	)
+
+	return nil
+}
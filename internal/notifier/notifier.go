@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/config"
+)
+
+const (
+	TypeLog  = "log"
+	TypeFile = "file"
+	TypeSMTP = "smtp"
+)
+
+var (
+	ErrUnknownNotifier = errors.New("unknown notifier type")
+	ErrNoNotifier      = errors.New("notifier type is not set")
+)
+
+type PasswordResetMessage struct {
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type EmailVerificationMessage struct {
+	Email     string    `json:"email"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Notifier delivers account messages to users. Implementations must not
+// keep the token anywhere it could be read back by other users.
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, msg *PasswordResetMessage) error
+	SendEmailVerification(ctx context.Context, msg *EmailVerificationMessage) error
+}
+
+func New(cfg config.NotifierConfig, logger *slog.Logger) (Notifier, error) {
+	switch cfg.Type {
+	// the log notifier writes tokens to the logs, it is never picked by default
+	case "":
+		return nil, ErrNoNotifier
+	case TypeLog:
+		return NewLogNotifier(logger), nil
+	case TypeFile:
+		return NewFileNotifier(cfg.FilePath)
+	case TypeSMTP:
+		return NewSMTPNotifier(cfg.SMTP)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownNotifier, cfg.Type)
+	}
+}
@@ -0,0 +1,107 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/config"
+)
+
+// SMTPNotifier sends messages as plain text emails.
+type SMTPNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+
+	resetPasswordURL string
+	verifyEmailURL   string
+}
+
+func NewSMTPNotifier(cfg config.SMTPConfig) (*SMTPNotifier, error) {
+	if cfg.Host == "" || cfg.From == "" {
+		return nil, errors.New("smtp notifier requires host and from")
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &SMTPNotifier{
+		addr:             net.JoinHostPort(cfg.Host, strconv.Itoa(int(port))),
+		auth:             auth,
+		from:             cfg.From,
+		resetPasswordURL: cfg.ResetPasswordURL,
+		verifyEmailURL:   cfg.VerifyEmailURL,
+	}, nil
+}
+
+func (n *SMTPNotifier) SendPasswordReset(ctx context.Context, msg *PasswordResetMessage) error {
+	body := fmt.Sprintf(
+		"Someone requested a password reset for your account.\r\n\r\n"+
+			"Use this link to set a new password: %s\r\n\r\n"+
+			"The link is valid until %s. If you did not request a reset, ignore this email.\r\n",
+		tokenLink(n.resetPasswordURL, msg.Token), msg.ExpiresAt.Format(time.RFC1123),
+	)
+
+	return n.send(msg.Email, "Password reset", body)
+}
+
+func (n *SMTPNotifier) SendEmailVerification(ctx context.Context, msg *EmailVerificationMessage) error {
+	body := fmt.Sprintf(
+		"Confirm your email address using this link: %s\r\n\r\n"+
+			"The link is valid until %s.\r\n",
+		tokenLink(n.verifyEmailURL, msg.Token), msg.ExpiresAt.Format(time.RFC1123),
+	)
+
+	return n.send(msg.Email, "Email verification", body)
+}
+
+func (n *SMTPNotifier) send(to string, subject string, body string) error {
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("invalid recipient address %q", to)
+	}
+
+	message := "From: " + n.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + body
+
+	if err := smtp.SendMail(n.addr, n.auth, n.from, []string{to}, []byte(message)); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// tokenLink puts the token into the "token" query parameter of base. The bare
+// token is returned when no link is configured.
+func tokenLink(base string, token string) string {
+	if base == "" {
+		return token
+	}
+
+	link, err := url.Parse(base)
+	if err != nil {
+		return token
+	}
+
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return link.String()
+}
@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/database"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/dormitory-life/auth/internal/publisher"
+	"github.com/dormitory-life/auth/internal/worker"
+)
+
+const (
+	defaultInterval  = time.Second
+	defaultBatchSize = 100
+
+	// lease keeps a claimed event away from other relays
+	lease = time.Minute
+)
+
+var retryBackoff = worker.Backoff{Min: 5 * time.Second, Max: 30 * time.Minute}
+
+// Relay publishes the events written to the outbox. Events are delivered at
+// least once, oldest first; an event that failed is retried with growing
+// delays while newer events go ahead.
+type Relay struct {
+	repository database.Repository
+	publisher  publisher.Publisher
+	logger     *slog.Logger
+	interval   time.Duration
+	batchSize  uint64
+}
+
+type Config struct {
+	Repository database.Repository
+	Publisher  publisher.Publisher
+	Logger     *slog.Logger
+	Interval   time.Duration
+	BatchSize  uint64
+}
+
+func New(cfg Config) *Relay {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultBatchSize
+	}
+
+	return &Relay{
+		repository: cfg.Repository,
+		publisher:  cfg.Publisher,
+		logger:     cfg.Logger,
+		interval:   interval,
+		batchSize:  batchSize,
+	}
+}
+
+// Run publishes pending events every interval until ctx is done. A full
+// batch is followed by the next one right away.
+func (r *Relay) Run(ctx context.Context) {
+	worker.RunBatches(ctx, r.interval, r.relay)
+}
+
+// relay publishes one batch of events and reports whether more may be due.
+func (r *Relay) relay(ctx context.Context) bool {
+	now := time.Now().UTC()
+
+	resp, err := r.repository.ClaimOutboxEvents(ctx, &dbtypes.ClaimOutboxEventsRequest{
+		Now:   now,
+		Lease: lease,
+		Limit: r.batchSize,
+	})
+	if err != nil {
+		r.logger.Error("error claiming outbox events", slog.String("error", err.Error()))
+		return false
+	}
+
+	deadline := worker.LeaseDeadline(now, lease)
+
+	for i := range resp.Events {
+		if ctx.Err() != nil || time.Now().UTC().After(deadline) {
+			return false
+		}
+
+		r.publish(ctx, &resp.Events[i])
+	}
+
+	return uint64(len(resp.Events)) == r.batchSize
+}
+
+func (r *Relay) publish(ctx context.Context, event *dbtypes.OutboxEvent) {
+	err := r.publisher.Publish(ctx, &publisher.Event{
+		Id:          event.EventId,
+		Type:        event.Type,
+		UserId:      event.UserId,
+		DormitoryId: event.DormitoryId,
+		OccurredAt:  event.CreatedAt,
+		Payload:     event.Payload,
+	})
+	if err != nil {
+		r.logger.Error("error publishing outbox event",
+			slog.String("error", err.Error()),
+			slog.String("event_id", event.EventId),
+			slog.String("type", event.Type),
+			slog.Int("attempts", event.Attempts),
+		)
+
+		err = r.repository.MarkOutboxEventFailed(ctx, &dbtypes.MarkOutboxEventFailedRequest{
+			Id:            event.Id,
+			NextAttemptAt: time.Now().UTC().Add(retryBackoff.Delay(event.Attempts)),
+			Error:         err.Error(),
+		})
+		if err != nil {
+			r.logger.Error("error marking outbox event failed",
+				slog.String("error", err.Error()),
+				slog.String("event_id", event.EventId),
+			)
+		}
+
+		return
+	}
+
+	err = r.repository.MarkOutboxEventPublished(ctx, &dbtypes.MarkOutboxEventPublishedRequest{
+		Id:          event.Id,
+		PublishedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		// the event is published again after the lease, consumers drop it
+		r.logger.Error("error marking outbox event published",
+			slog.String("error", err.Error()),
+			slog.String("event_id", event.EventId),
+		)
+	}
+}
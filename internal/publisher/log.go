@@ -0,0 +1,30 @@
+package publisher
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogPublisher writes events to the service log. It is meant for local
+// development only.
+type LogPublisher struct {
+	logger *slog.Logger
+}
+
+func NewLogPublisher(logger *slog.Logger) *LogPublisher {
+	return &LogPublisher{
+		logger: logger,
+	}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event *Event) error {
+	p.logger.Info("event published",
+		slog.String("event_id", event.Id),
+		slog.String("type", event.Type),
+		slog.String("user_id", event.UserId),
+		slog.String("dormitory_id", event.DormitoryId),
+		slog.String("payload", string(event.Payload)),
+	)
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+)
+
+// MultiPublisher publishes every event to each of its publishers. An event
+// counts as published once all of them took it; until then it is published
+// to all of them again.
+type MultiPublisher struct {
+	publishers []Publisher
+}
+
+func NewMultiPublisher(publishers ...Publisher) *MultiPublisher {
+	return &MultiPublisher{
+		publishers: publishers,
+	}
+}
+
+func (p *MultiPublisher) Publish(ctx context.Context, event *Event) error {
+	var errs []error
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,50 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/config"
+)
+
+const (
+	TypeLog     = "log"
+	TypeFile    = "file"
+	TypeWebhook = "webhook"
+)
+
+var ErrUnknownPublisher = errors.New("unknown publisher type")
+
+// Event is a domain event as other services receive it. Events are delivered
+// at least once; consumers drop repeats by Id.
+type Event struct {
+	Id          string          `json:"id"`
+	Type        string          `json:"type"`
+	UserId      string          `json:"user_id"`
+	DormitoryId string          `json:"dormitory_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// Publisher delivers events to other services. An error means the event was
+// not delivered and is retried later.
+type Publisher interface {
+	Publish(ctx context.Context, event *Event) error
+}
+
+func New(cfg config.PublisherConfig, logger *slog.Logger) (Publisher, error) {
+	switch cfg.Type {
+	case TypeLog, "":
+		return NewLogPublisher(logger), nil
+	case TypeFile:
+		return NewFilePublisher(cfg.FilePath)
+	case TypeWebhook:
+		return NewWebhookPublisher(cfg.Webhook)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPublisher, cfg.Type)
+	}
+}
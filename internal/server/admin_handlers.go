@@ -0,0 +1,460 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+	auth "github.com/dormitory-life/auth/internal/service"
+	"github.com/google/uuid"
+)
+
+// @Summary Список пользователей общежития
+// @Description Возвращает пользователей общежития администратора с пагинацией, фильтрами и сортировкой
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param role query string false "Фильтр по роли"
+// @Param status query string false "Фильтр по статусу: active, suspended, banned, graduated"
+// @Param email query string false "Подстрока email"
+// @Param created_from query string false "Созданы не раньше (RFC 3339)"
+// @Param created_to query string false "Созданы раньше (RFC 3339)"
+// @Param sort query string false "Поле сортировки: created_at, email, role" default(created_at)
+// @Param order query string false "Порядок сортировки: asc, desc" default(asc)
+// @Param limit query int false "Размер страницы (не больше 100)" default(20)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {object} rmodel.ListUsersResponse "Пользователи"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users [get]
+func (s *Server) listUsersHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "listUsersHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	req, err := parseListUsersQuery(r.URL.Query())
+	if err != nil {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.DormitoryId = dormitoryId
+
+	resp, err := s.authService.ListUsers(r.Context(), req)
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Пользователь общежития
+// @Description Возвращает пользователя общежития администратора
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор пользователя"
+// @Success 200 {object} rmodel.AdminUser "Пользователь"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверный идентификатор пользователя"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id} [get]
+func (s *Server) adminGetUserHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "adminGetUserHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	resp, err := s.authService.AdminGetUser(r.Context(), &rmodel.AdminGetUserRequest{
+		DormitoryId: dormitoryId,
+		UserId:      userId,
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Изменение пользователя
+// @Description Меняет роль или статус пользователя общежития администратора. При смене статуса все сессии пользователя завершаются. Роль и статус меняются вместе: если одно изменение отклонено, второе не применяется
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор пользователя"
+// @Param request body rmodel.AdminUpdateUserRequest true "Изменяемые поля"
+// @Success 200 {object} rmodel.AdminUser "Пользователь изменен"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав / пользователь имеет роль super_admin"
+// @Failure 404 {object} rmodel.ErrorResponse "Пользователь не найден"
+// @Failure 409 {object} rmodel.ErrorResponse "Нельзя понизить или заблокировать последнего администратора / действует временная роль"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id} [patch]
+func (s *Server) adminUpdateUserHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "adminUpdateUserHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	var req rmodel.AdminUpdateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	req.ActorId = principal.UserId
+	req.DormitoryId = dormitoryId
+	req.UserId = userId
+
+	s.logger.Debug(handlerName, slog.Any("req", req), slog.String("actor_id", principal.UserId))
+
+	resp, err := s.authService.AdminUpdateUser(r.Context(), &req)
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// adminDormitoryId is the dormitory an admin request acts on: the one given
+// in the dormitory_id query parameter, or the dormitory of the admin. It
+// answers 403 itself when the admin has no scope for the dormitory.
+func (s *Server) adminDormitoryId(
+	w http.ResponseWriter,
+	r *http.Request,
+	principal *auth.Principal,
+) (string, bool) {
+	dormitoryId := r.URL.Query().Get("dormitory_id")
+	if dormitoryId == "" {
+		return principal.DormitoryId, true
+	}
+
+	if !principal.InDormitory(dormitoryId) {
+		writeErrorResponse(w, constants.ErrForbidden, http.StatusForbidden, "No access to the dormitory")
+		s.logger.Info("access denied",
+			slog.String("user_id", principal.UserId),
+			slog.String("dormitory_id", dormitoryId),
+			slog.String("url", r.URL.Path),
+		)
+
+		return "", false
+	}
+
+	return dormitoryId, true
+}
+
+// pathId reads the path parameter name of an admin request as a UUID. It
+// answers 400 itself for a malformed id, which the database would fail to
+// cast; what names the id in the message.
+func (s *Server) pathId(w http.ResponseWriter, r *http.Request, name string, what string) (string, bool) {
+	id := r.PathValue(name)
+	if _, err := uuid.Parse(id); err != nil {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, fmt.Sprintf("Malformed %s id", what))
+		return "", false
+	}
+
+	return id, true
+}
+
+func parseListUsersQuery(query url.Values) (*rmodel.ListUsersRequest, error) {
+	req := &rmodel.ListUsersRequest{
+		Role:          query.Get("role"),
+		Status:        query.Get("status"),
+		EmailContains: query.Get("email"),
+		SortBy:        query.Get("sort"),
+	}
+
+	switch order := query.Get("order"); order {
+	case "", "asc":
+	case "desc":
+		req.SortDesc = true
+	default:
+		return nil, fmt.Errorf("invalid order %q", order)
+	}
+
+	for name, target := range map[string]**time.Time{
+		"created_from": &req.CreatedFrom,
+		"created_to":   &req.CreatedTo,
+	} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: expected RFC 3339 time", name)
+		}
+
+		*target = &parsed
+	}
+
+	if err := parsePagination(query, &req.Limit, &req.Offset); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func parsePagination(query url.Values, limit *uint64, offset *uint64) error {
+	for name, target := range map[string]*uint64{
+		"limit":  limit,
+		"offset": offset,
+	} {
+		value := query.Get(name)
+		if value == "" {
+			continue
+		}
+
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid %s: expected non-negative integer", name)
+		}
+
+		*target = parsed
+	}
+
+	return nil
+}
+
+// @Summary Смена роли пользователя
+// @Description Назначает или снимает роль пользователя общежития администратора. Последнего активного администратора общежития понизить нельзя, как и сменить роль, пока действует временная роль пользователя. Администраторы только по временной роли не считаются. Каждое изменение записывается в журнал
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор пользователя"
+// @Param request body rmodel.ChangeUserRoleRequest true "Новая роль"
+// @Success 200 {object} rmodel.AdminUser "Роль изменена"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Пользователь не найден"
+// @Failure 409 {object} rmodel.ErrorResponse "Нельзя понизить последнего администратора / действует временная роль"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/role [put]
+func (s *Server) changeUserRoleHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "changeUserRoleHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	var req rmodel.ChangeUserRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	if req.Role == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing role")
+		return
+	}
+
+	req.ActorId = principal.UserId
+	req.DormitoryId = dormitoryId
+	req.UserId = userId
+
+	resp, err := s.authService.ChangeUserRole(r.Context(), &req)
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("user role changed",
+		slog.String("user_id", resp.UserId),
+		slog.String("role", resp.Role),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Журнал смены ролей
+// @Description Возвращает изменения ролей пользователей общежития администратора, новые первыми
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param user_id query string false "Только изменения этого пользователя"
+// @Param limit query int false "Размер страницы (не больше 100)" default(20)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {object} rmodel.ListRoleChangesResponse "Изменения ролей"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/role-changes [get]
+func (s *Server) listRoleChangesHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "listRoleChangesHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	req := &rmodel.ListRoleChangesRequest{
+		DormitoryId: dormitoryId,
+		UserId:      r.URL.Query().Get("user_id"),
+	}
+
+	if err := parsePagination(r.URL.Query(), &req.Limit, &req.Offset); err != nil {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.authService.ListRoleChanges(r.Context(), req)
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
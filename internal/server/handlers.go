@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strings"
 
 	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
 	auth "github.com/dormitory-life/auth/internal/service"
@@ -23,7 +24,7 @@ func (s *Server) pingHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Регистрация нового пользователя
-// @Description Создает нового пользователя в системе
+// @Description Создает нового пользователя в системе и отправляет письмо для подтверждения email. Если подтверждение обязательно, токены не выдаются
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -47,7 +48,12 @@ func (s *Server) registerHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.logger.Debug(handlerName, slog.Any("req", req))
+	// the password stays out of the logs
+	s.logger.Debug(handlerName,
+		slog.String("email", req.Email),
+		slog.String("dormitory_id", req.DormitoryId),
+		slog.String("client_id", req.ClientId),
+	)
 
 	resp, err := s.authService.Register(r.Context(), &req)
 	if err != nil {
@@ -81,6 +87,7 @@ func (s *Server) registerHandler(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} rmodel.LoginResponse "Пользователь авторизован"
 // @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
 // @Failure 401 {object} rmodel.ErrorResponse "Неверные данные для входа"
+// @Failure 403 {object} rmodel.ErrorResponse "Email не подтвержден или аккаунт заблокирован"
 // @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /auth/login [post]
 func (s *Server) loginHandler(w http.ResponseWriter, r *http.Request) {
@@ -97,7 +104,11 @@ func (s *Server) loginHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	s.logger.Debug(handlerName, slog.Any("req", req))
+	// the password stays out of the logs
+	s.logger.Debug(handlerName,
+		slog.String("email", req.Email),
+		slog.String("client_id", req.ClientId),
+	)
 
 	resp, err := s.authService.Login(r.Context(), &req)
 	if err != nil {
@@ -123,27 +134,77 @@ func (s *Server) loginHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Обновление токенов
-// @Description Выдает пользователю пару новых токенов
+// @Description Обменивает refresh-токен на пару новых токенов. Использованный refresh-токен становится недействительным, повторное его предъявление отзывает всю сессию
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body rmodel.RefreshTokensRequest true "Пара старых токенов"
 // @Success 200 {object} rmodel.RefreshTokensResponse "Новые токены выданы"
 // @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Refresh-токен недействителен, истек или отозван"
+// @Failure 403 {object} rmodel.ErrorResponse "Аккаунт заблокирован"
 // @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /auth/refresh [post]
 func (s *Server) refreshHandler(w http.ResponseWriter, r *http.Request) {
 	const handlerName = "refreshHandler"
 
-	userId := r.Header.Get("X-User-ID")
-	dormitoryId := r.Header.Get("X-Dormitory-ID")
+	var req rmodel.RefreshTokensRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
 
-	if userId == "" || dormitoryId == "" {
-		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing user data")
 		return
 	}
 
-	var req rmodel.RefreshTokensRequest
+	if req.RefreshToken == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing refresh token")
+		return
+	}
+
+	// the tokens stay out of the logs, a leaked refresh token opens a session
+	s.logger.Debug(handlerName)
+
+	resp, err := s.authService.RefreshTokens(r.Context(), &req)
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		writeErrorResponse(w, err, http.StatusInternalServerError)
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Выход из системы
+// @Description Отзывает refresh-токен и завершает текущую сессию пользователя
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body rmodel.LogoutRequest true "Refresh-токен текущей сессии"
+// @Success 204 "Сессия завершена"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Refresh-токен недействителен"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /auth/logout [post]
+func (s *Server) logoutHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "logoutHandler"
+
+	var req rmodel.LogoutRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeErrorResponse(w, err, http.StatusBadRequest)
 		s.logger.Error("error decoding request",
@@ -153,12 +214,336 @@ func (s *Server) refreshHandler(w http.ResponseWriter, r *http.Request) {
 
 		return
 	}
-	req.UserId = userId
-	req.DormitoryId = dormitoryId
 
-	s.logger.Debug(handlerName, slog.Any("req", req))
+	if req.RefreshToken == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing refresh token")
+		return
+	}
 
-	resp, err := s.authService.RefreshTokens(r.Context(), &req)
+	if err := s.authService.Logout(r.Context(), &req); err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Выход со всех устройств
+// @Description Отзывает все refresh-токены пользователя, которому принадлежит переданный токен
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body rmodel.LogoutRequest true "Refresh-токен текущей сессии"
+// @Success 204 "Все сессии пользователя завершены"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Refresh-токен недействителен"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /auth/logout-all [post]
+func (s *Server) logoutAllHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "logoutAllHandler"
+
+	var req rmodel.LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	if req.RefreshToken == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing refresh token")
+		return
+	}
+
+	if err := s.authService.LogoutAll(r.Context(), &req); err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Запрос на сброс пароля
+// @Description Создает одноразовый токен сброса пароля и отправляет его пользователю. Ответ не зависит от того, зарегистрирован ли email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body rmodel.ForgotPasswordRequest true "Email пользователя"
+// @Success 202 "Запрос принят"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /auth/password/forgot [post]
+func (s *Server) forgotPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "forgotPasswordHandler"
+
+	var req rmodel.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	if req.Email == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing email")
+		return
+	}
+
+	if err := s.authService.ForgotPassword(r.Context(), &req); err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// @Summary Сброс пароля
+// @Description Устанавливает новый пароль по токену сброса и завершает все сессии пользователя. Токен можно использовать только один раз
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body rmodel.ResetPasswordRequest true "Токен сброса и новый пароль"
+// @Success 204 "Пароль изменен"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные, токен недействителен или истек"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /auth/password/reset [post]
+func (s *Server) resetPasswordHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "resetPasswordHandler"
+
+	var req rmodel.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	if req.Token == "" || req.NewPassword == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing token or new password")
+		return
+	}
+
+	if err := s.authService.ResetPassword(r.Context(), &req); err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Смена пароля
+// @Description Меняет пароль авторизованного пользователя после проверки текущего пароля. При revoke_other_sessions=true завершает все сессии, кроме текущей
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body rmodel.ChangePasswordRequest true "Текущий и новый пароль"
+// @Success 204 "Пароль изменен"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Неверный текущий пароль"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /auth/password/change [post]
+func (s *Server) changePasswordHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "changePasswordHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	var req rmodel.ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing current or new password")
+		return
+	}
+
+	req.UserId = principal.UserId
+	req.SessionId = principal.SessionId
+
+	if err := s.authService.ChangePassword(r.Context(), &req); err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Подтверждение email
+// @Description Подтверждает email пользователя по одноразовому токену из письма
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body rmodel.VerifyEmailRequest true "Токен подтверждения"
+// @Success 204 "Email подтвержден"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные, токен недействителен или истек"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /auth/email/verify [post]
+func (s *Server) verifyEmailHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "verifyEmailHandler"
+
+	var req rmodel.VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	if req.Token == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing token")
+		return
+	}
+
+	if err := s.authService.VerifyEmail(r.Context(), &req); err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Повторная отправка письма с подтверждением
+// @Description Отправляет новый токен подтверждения email, предыдущие токены перестают действовать. Ответ не зависит от того, зарегистрирован ли email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body rmodel.ResendVerificationEmailRequest true "Email пользователя"
+// @Success 202 "Запрос принят"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /auth/email/resend [post]
+func (s *Server) resendVerificationEmailHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "resendVerificationEmailHandler"
+
+	var req rmodel.ResendVerificationEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	if req.Email == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing email")
+		return
+	}
+
+	if err := s.authService.ResendVerificationEmail(r.Context(), &req); err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// @Summary Интроспекция токена
+// @Description Проверяет подпись, срок действия и отзыв access-токена (RFC 7662). Для недействительного токена возвращает active=false. Вызывающий сервис передает client id и секрет из server.introspection_clients через HTTP Basic
+// @Tags auth
+// @Accept json
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Security ClientBasicAuth
+// @Param request body rmodel.IntrospectRequest true "Проверяемый токен"
+// @Success 200 {object} rmodel.IntrospectResponse "Результат проверки токена"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Неверные учетные данные сервиса"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /auth/introspect [post]
+func (s *Server) introspectHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "introspectHandler"
+
+	var req rmodel.IntrospectRequest
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			writeErrorResponse(w, err, http.StatusBadRequest)
+			s.logger.Error("error parsing form",
+				slog.String("error", err.Error()),
+				slog.String("handler", handlerName),
+			)
+
+			return
+		}
+
+		req.Token = r.PostForm.Get("token")
+		req.TokenTypeHint = r.PostForm.Get("token_type_hint")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	if req.Token == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing token")
+		return
+	}
+
+	resp, err := s.authService.IntrospectToken(r.Context(), &req)
 	if err != nil {
 		s.handleError(w, err)
 		s.logger.Error("error",
@@ -169,8 +554,43 @@ func (s *Server) refreshHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
 	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		writeErrorResponse(w, err, http.StatusInternalServerError)
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Публичные ключи подписи токенов
+// @Description Возвращает JWKS с публичными ключами, которыми другие сервисы могут проверять подпись токенов. При подписи общим секретом (HS256) список ключей пуст
+// @Tags auth
+// @Produce json
+// @Success 200 {object} rmodel.JWKSResponse "Набор публичных ключей"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /.well-known/jwks.json [get]
+func (s *Server) jwksHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "jwksHandler"
+
+	resp, err := s.authService.GetJWKS(r.Context())
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
 	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
 
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
 		writeErrorResponse(w, err, http.StatusInternalServerError)
@@ -181,6 +601,18 @@ func (s *Server) refreshHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
+
 func writeErrorResponse(w http.ResponseWriter, err error, code int, details ...string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(code)
@@ -203,6 +635,8 @@ func (s *Server) handleError(w http.ResponseWriter, err error) {
 		writeErrorResponse(w, constants.ErrConflict, http.StatusConflict, err.Error())
 	case errors.Is(err, auth.ErrUnauthorized):
 		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+	case errors.Is(err, auth.ErrForbidden):
+		writeErrorResponse(w, constants.ErrForbidden, http.StatusForbidden, err.Error())
 	case errors.Is(err, auth.ErrInternal):
 		writeErrorResponse(w, constants.ErrInternalServerError, http.StatusInternalServerError)
 	default:
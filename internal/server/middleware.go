@@ -1,11 +1,19 @@
 package server
 
 import (
+	"context"
+	"crypto/subtle"
+	"errors"
 	"log/slog"
 	"net/http"
 	"time"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	auth "github.com/dormitory-life/auth/internal/service"
 )
 
+type principalContextKey struct{}
+
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		s.logger.Info("request started",
@@ -21,3 +29,78 @@ func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 			slog.Any("date", time.Now().UTC()))
 	})
 }
+
+// authMiddleware lets the request through only with a valid access token of
+// an active session. When roles are given, the caller must have one of them.
+// The authenticated principal is available via PrincipalFromContext.
+func (s *Server) authMiddleware(next http.HandlerFunc, roles ...string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accessToken, ok := bearerToken(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer`)
+			writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized, "Missing bearer token")
+			return
+		}
+
+		principal, err := s.authService.Authenticate(r.Context(), accessToken)
+		if err != nil {
+			if errors.Is(err, auth.ErrUnauthorized) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			}
+
+			s.handleError(w, err)
+			s.logger.Debug("authentication failed",
+				slog.String("error", err.Error()),
+				slog.String("url", r.URL.Path),
+			)
+
+			return
+		}
+
+		if len(roles) > 0 && !principal.HasRole(roles...) {
+			writeErrorResponse(w, constants.ErrForbidden, http.StatusForbidden, "Insufficient role")
+			s.logger.Info("access denied",
+				slog.String("user_id", principal.UserId),
+				slog.String("role", principal.Role),
+				slog.String("url", r.URL.Path),
+			)
+
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), principal)))
+	})
+}
+
+// clientAuthMiddleware lets the request through only for a service that
+// sends its credentials from the introspection clients with HTTP Basic auth,
+// as RFC 7662 requires of introspection callers.
+func (s *Server) clientAuthMiddleware(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientId, secret, ok := r.BasicAuth()
+		expected := s.introspectionClients[clientId]
+
+		if !ok || expected == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="introspection"`)
+			writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized, "Invalid client credentials")
+			s.logger.Info("client authentication failed",
+				slog.String("client_id", clientId),
+				slog.String("url", r.URL.Path),
+			)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func WithPrincipal(ctx context.Context, principal *auth.Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the caller authenticated by authMiddleware.
+func PrincipalFromContext(ctx context.Context) (*auth.Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*auth.Principal)
+	return principal, ok && principal != nil
+}
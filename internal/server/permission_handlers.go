@@ -0,0 +1,243 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+// @Summary Список разрешений
+// @Description Возвращает все разрешения сервиса. Разрешения с grantable=false выдаются только вместе с ролью
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} rmodel.ListPermissionsResponse "Разрешения"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/permissions [get]
+func (s *Server) listPermissionsHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "listPermissionsHandler"
+
+	resp, err := s.authService.ListPermissions(r.Context())
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Разрешения пользователя
+// @Description Возвращает разрешения роли пользователя общежития администратора и выданные ему отдельно
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор пользователя"
+// @Success 200 {object} rmodel.UserPermissionsResponse "Разрешения пользователя"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверный идентификатор пользователя"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Пользователь не найден"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/permissions [get]
+func (s *Server) getUserPermissionsHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "getUserPermissionsHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	resp, err := s.authService.GetUserPermissions(r.Context(), &rmodel.UserPermissionsRequest{
+		DormitoryId: dormitoryId,
+		UserId:      userId,
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Выдача разрешения
+// @Description Выдает разрешение пользователю общежития администратора в дополнение к разрешениям его роли. Тело запроса необязательно: в нем можно ограничить срок действия разрешения. Повторная выдача заменяет срок действия
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор пользователя"
+// @Param permission path string true "Название разрешения, например news.publish"
+// @Param request body rmodel.GrantPermissionRequest false "Срок действия разрешения"
+// @Success 200 {object} rmodel.UserPermissionsResponse "Разрешения пользователя"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверный срок действия / разрешение выдается только вместе с ролью"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Пользователь или разрешение не найдены"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/permissions/{permission} [put]
+func (s *Server) grantPermissionHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "grantPermissionHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	// the body is optional, without it the permission does not expire
+	var req rmodel.GrantPermissionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	req.ActorId = principal.UserId
+	req.DormitoryId = dormitoryId
+	req.UserId = userId
+	req.Permission = r.PathValue("permission")
+
+	resp, err := s.authService.GrantPermission(r.Context(), &req)
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("permission granted",
+		slog.String("user_id", resp.UserId),
+		slog.String("permission", r.PathValue("permission")),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Отзыв разрешения
+// @Description Отзывает выданное пользователю разрешение. Разрешения роли пользователя не затрагиваются
+// @Tags admin
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор пользователя"
+// @Param permission path string true "Название разрешения"
+// @Success 204 "Разрешение отозвано"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверный идентификатор пользователя"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Разрешение не было выдано"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/permissions/{permission} [delete]
+func (s *Server) revokePermissionHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "revokePermissionHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	err := s.authService.RevokePermission(r.Context(), &rmodel.RevokePermissionRequest{
+		DormitoryId: dormitoryId,
+		UserId:      userId,
+		Permission:  r.PathValue("permission"),
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("permission revoked",
+		slog.String("user_id", userId),
+		slog.String("permission", r.PathValue("permission")),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package requestmodels
+
+import (
+	"time"
+
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+type AdminUser struct {
+	UserId          string     `json:"user_id"`
+	Email           string     `json:"email"`
+	DormitoryId     string     `json:"dormitory_id"`
+	Role            string     `json:"role"`
+	CreatedAt       time.Time  `json:"created_at"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	Status          string     `json:"status"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusExpiresAt *time.Time `json:"status_expires_at,omitempty"`
+}
+
+func (*AdminUser) From(msg *dbtypes.User) *AdminUser {
+	if msg == nil {
+		return nil
+	}
+
+	return &AdminUser{
+		UserId:          msg.UserId,
+		Email:           msg.Email,
+		DormitoryId:     msg.DormitoryId,
+		Role:            msg.Role,
+		CreatedAt:       msg.CreatedAt,
+		EmailVerifiedAt: msg.EmailVerifiedAt,
+		Status:          msg.Status,
+		StatusReason:    msg.StatusReason,
+		StatusExpiresAt: msg.StatusExpiresAt,
+	}
+}
+
+type (
+	// ListUsersRequest is built from the query string. DormitoryId is the
+	// dormitory of the calling admin.
+	ListUsersRequest struct {
+		DormitoryId string `json:"-"`
+
+		Role          string
+		Status        string
+		EmailContains string
+		CreatedFrom   *time.Time
+		CreatedTo     *time.Time
+		SortBy        string
+		SortDesc      bool
+		Limit         uint64
+		Offset        uint64
+	}
+
+	ListUsersResponse struct {
+		Users  []*AdminUser `json:"users"`
+		Total  uint64       `json:"total"`
+		Limit  uint64       `json:"limit"`
+		Offset uint64       `json:"offset"`
+	}
+)
+
+type AdminGetUserRequest struct {
+	DormitoryId string `json:"-"`
+	UserId      string `json:"-"`
+}
+
+type AdminUpdateUserRequest struct {
+	ActorId     string `json:"-"`
+	DormitoryId string `json:"-"`
+	UserId      string `json:"-"`
+
+	Role *string `json:"role,omitempty"`
+	// Status changes revoke all sessions of the user. StatusReason and
+	// StatusExpiresAt are stored with the new status; after StatusExpiresAt
+	// the user is active again.
+	Status          *string    `json:"status,omitempty"`
+	StatusReason    string     `json:"status_reason,omitempty"`
+	StatusExpiresAt *time.Time `json:"status_expires_at,omitempty"`
+}
@@ -20,13 +20,17 @@ type (
 		Email       string `json:"email"`
 		Password    string `json:"password"`
 		DormitoryId string `json:"dormitory_id"`
+		ClientId    string `json:"client_id,omitempty"`
 	}
 
 	RegisterResponse struct {
 		UserId       string `json:"user_id"`
 		DormitoryId  string `json:"dormitory_id"`
-		AccessToken  string `json:"access_token"`
-		RefreshToken string `json:"refresh_token"`
+		AccessToken  string `json:"access_token,omitempty"`
+		RefreshToken string `json:"refresh_token,omitempty"`
+		// EmailVerificationRequired is set when tokens are not issued until
+		// the email is verified.
+		EmailVerificationRequired bool `json:"email_verification_required,omitempty"`
 	}
 )
 
@@ -34,6 +38,7 @@ type (
 	LoginRequest struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		ClientId string `json:"client_id,omitempty"`
 	}
 
 	LoginResponse struct {
@@ -59,6 +64,7 @@ type UserInfoById struct {
 	UserId      string
 	DormitoryId string
 	Role        string
+	Status      string
 }
 
 type (
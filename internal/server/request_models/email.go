@@ -0,0 +1,9 @@
+package requestmodels
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type ResendVerificationEmailRequest struct {
+	Email string `json:"email"`
+}
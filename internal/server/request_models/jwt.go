@@ -1,9 +1,9 @@
 package requestmodels
 
+import "github.com/dormitory-life/auth/internal/jwtkeys"
+
 type (
 	RefreshTokensRequest struct {
-		UserId       string
-		DormitoryId  string
 		AccessToken  string `json:"access_token"`
 		RefreshToken string `json:"refresh_token"`
 	}
@@ -13,3 +13,64 @@ type (
 		RefreshToken string `json:"refresh_token"`
 	}
 )
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type (
+	JWK struct {
+		Kty string `json:"kty"`
+		Use string `json:"use,omitempty"`
+		Kid string `json:"kid,omitempty"`
+		Alg string `json:"alg,omitempty"`
+		N   string `json:"n,omitempty"`
+		E   string `json:"e,omitempty"`
+		Crv string `json:"crv,omitempty"`
+		X   string `json:"x,omitempty"`
+		Y   string `json:"y,omitempty"`
+	}
+
+	JWKSResponse struct {
+		Keys []JWK `json:"keys"`
+	}
+)
+
+func (*JWKSResponse) From(msg *jwtkeys.JWKSet) *JWKSResponse {
+	if msg == nil {
+		return nil
+	}
+
+	keys := make([]JWK, 0, len(msg.Keys))
+	for _, key := range msg.Keys {
+		keys = append(keys, JWK(key))
+	}
+
+	return &JWKSResponse{
+		Keys: keys,
+	}
+}
+
+type (
+	IntrospectRequest struct {
+		Token         string `json:"token"`
+		TokenTypeHint string `json:"token_type_hint,omitempty"`
+	}
+
+	IntrospectResponse struct {
+		Active      bool     `json:"active"`
+		Subject     string   `json:"sub,omitempty"`
+		UserId      string   `json:"user_id,omitempty"`
+		DormitoryId string   `json:"dormitory_id,omitempty"`
+		Dormitories []string `json:"dormitories,omitempty"`
+		Role        string   `json:"role,omitempty"`
+		SessionId   string   `json:"sid,omitempty"`
+		ClientId    string   `json:"client_id,omitempty"`
+		TokenType   string   `json:"token_type,omitempty"`
+		Issuer      string   `json:"iss,omitempty"`
+		Audience    []string `json:"aud,omitempty"`
+		JwtId       string   `json:"jti,omitempty"`
+		ExpiresAt   int64    `json:"exp,omitempty"`
+		IssuedAt    int64    `json:"iat,omitempty"`
+	}
+)
@@ -0,0 +1,115 @@
+package requestmodels
+
+import (
+	"time"
+
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+type (
+	CheckPermissionRequest struct {
+		UserId      string
+		DormitoryId string
+		Permission  string
+	}
+
+	CheckPermissionResponse struct {
+		Allowed  bool
+		Reason   string
+		UserRole string
+		// DenialReason is one of the constants.DenialReason values, empty
+		// when allowed.
+		DenialReason string
+	}
+
+	BatchCheckPermissionRequest struct {
+		Checks []CheckPermissionRequest
+	}
+
+	BatchCheckPermissionResponse struct {
+		// Results are in the order of the checks.
+		Results []*CheckPermissionResponse
+	}
+)
+
+type Permission struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Grantable   bool   `json:"grantable"`
+}
+
+func (*Permission) From(msg *dbtypes.Permission) *Permission {
+	if msg == nil {
+		return nil
+	}
+
+	return &Permission{
+		Name:        msg.Name,
+		Description: msg.Description,
+		Grantable:   msg.Grantable,
+	}
+}
+
+type ListPermissionsResponse struct {
+	Permissions []*Permission `json:"permissions"`
+}
+
+type PermissionGrant struct {
+	Permission string     `json:"permission"`
+	GrantedBy  *string    `json:"granted_by"`
+	GrantedAt  time.Time  `json:"granted_at"`
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil *time.Time `json:"valid_until,omitempty"`
+}
+
+func (*PermissionGrant) From(msg *dbtypes.PermissionGrant) *PermissionGrant {
+	if msg == nil {
+		return nil
+	}
+
+	return &PermissionGrant{
+		Permission: msg.Permission,
+		GrantedBy:  msg.GrantedBy,
+		GrantedAt:  msg.GrantedAt,
+		ValidFrom:  msg.ValidFrom,
+		ValidUntil: msg.ValidUntil,
+	}
+}
+
+type (
+	// UserPermissionsRequest is built from the path. DormitoryId is the
+	// dormitory of the calling admin.
+	UserPermissionsRequest struct {
+		DormitoryId string
+		UserId      string
+	}
+
+	UserPermissionsResponse struct {
+		UserId string `json:"user_id"`
+		Role   string `json:"role"`
+		// RolePermissions come with the role of the user, Grants were given
+		// to the user on top of it.
+		RolePermissions []string           `json:"role_permissions"`
+		Grants          []*PermissionGrant `json:"grants"`
+	}
+)
+
+type (
+	GrantPermissionRequest struct {
+		ActorId     string `json:"-"`
+		DormitoryId string `json:"-"`
+		UserId      string `json:"-"`
+		Permission  string `json:"-"`
+
+		// ValidFrom and ValidUntil limit the grant in time. Without them it
+		// applies at once and does not expire.
+		ValidFrom  *time.Time `json:"valid_from,omitempty"`
+		ValidUntil *time.Time `json:"valid_until,omitempty"`
+	}
+
+	RevokePermissionRequest struct {
+		DormitoryId string
+		UserId      string
+		Permission  string
+	}
+)
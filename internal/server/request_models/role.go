@@ -0,0 +1,58 @@
+package requestmodels
+
+import (
+	"time"
+
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+type ChangeUserRoleRequest struct {
+	ActorId     string `json:"-"`
+	DormitoryId string `json:"-"`
+	UserId      string `json:"-"`
+
+	Role string `json:"role"`
+}
+
+type RoleChange struct {
+	Id          string    `json:"id"`
+	UserId      string    `json:"user_id"`
+	OldRole     string    `json:"old_role"`
+	NewRole     string    `json:"new_role"`
+	ActorId     *string   `json:"actor_id"`
+	ActorSource string    `json:"actor_source"`
+	ChangedAt   time.Time `json:"changed_at"`
+}
+
+func (*RoleChange) From(msg *dbtypes.RoleChange) *RoleChange {
+	if msg == nil {
+		return nil
+	}
+
+	return &RoleChange{
+		Id:          msg.Id,
+		UserId:      msg.UserId,
+		OldRole:     msg.OldRole,
+		NewRole:     msg.NewRole,
+		ActorId:     msg.ActorId,
+		ActorSource: msg.ActorSource,
+		ChangedAt:   msg.ChangedAt,
+	}
+}
+
+type (
+	// ListRoleChangesRequest is built from the query string. DormitoryId is
+	// the dormitory of the calling admin.
+	ListRoleChangesRequest struct {
+		DormitoryId string
+		UserId      string
+		Limit       uint64
+		Offset      uint64
+	}
+
+	ListRoleChangesResponse struct {
+		Changes []*RoleChange `json:"changes"`
+		Limit   uint64        `json:"limit"`
+		Offset  uint64        `json:"offset"`
+	}
+)
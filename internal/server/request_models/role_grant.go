@@ -0,0 +1,65 @@
+package requestmodels
+
+import (
+	"time"
+
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+type RoleGrant struct {
+	Id         string     `json:"id"`
+	UserId     string     `json:"user_id"`
+	Role       string     `json:"role"`
+	ValidFrom  time.Time  `json:"valid_from"`
+	ValidUntil time.Time  `json:"valid_until"`
+	GrantedBy  *string    `json:"granted_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	ExpiredAt  *time.Time `json:"expired_at,omitempty"`
+}
+
+func (*RoleGrant) From(msg *dbtypes.RoleGrant) *RoleGrant {
+	if msg == nil {
+		return nil
+	}
+
+	return &RoleGrant{
+		Id:         msg.Id,
+		UserId:     msg.UserId,
+		Role:       msg.Role,
+		ValidFrom:  msg.ValidFrom,
+		ValidUntil: msg.ValidUntil,
+		GrantedBy:  msg.GrantedBy,
+		CreatedAt:  msg.CreatedAt,
+		StartedAt:  msg.StartedAt,
+		ExpiredAt:  msg.ExpiredAt,
+	}
+}
+
+type CreateRoleGrantRequest struct {
+	ActorId     string `json:"-"`
+	DormitoryId string `json:"-"`
+	UserId      string `json:"-"`
+
+	Role string `json:"role"`
+	// ValidFrom defaults to now.
+	ValidFrom  *time.Time `json:"valid_from,omitempty"`
+	ValidUntil time.Time  `json:"valid_until"`
+}
+
+type (
+	ListRoleGrantsRequest struct {
+		DormitoryId string
+		UserId      string
+	}
+
+	ListRoleGrantsResponse struct {
+		Grants []*RoleGrant `json:"grants"`
+	}
+)
+
+type EndRoleGrantRequest struct {
+	DormitoryId string
+	UserId      string
+	GrantId     string
+}
@@ -0,0 +1,49 @@
+package requestmodels
+
+import (
+	"time"
+
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+type DormitoryScope struct {
+	DormitoryId string    `json:"dormitory_id"`
+	GrantedBy   *string   `json:"granted_by"`
+	GrantedAt   time.Time `json:"granted_at"`
+}
+
+func (*DormitoryScope) From(msg *dbtypes.DormitoryScope) *DormitoryScope {
+	if msg == nil {
+		return nil
+	}
+
+	return &DormitoryScope{
+		DormitoryId: msg.DormitoryId,
+		GrantedBy:   msg.GrantedBy,
+		GrantedAt:   msg.GrantedAt,
+	}
+}
+
+type (
+	DormitoryScopesRequest struct {
+		UserId string
+	}
+
+	DormitoryScopesResponse struct {
+		UserId string            `json:"user_id"`
+		Scopes []*DormitoryScope `json:"scopes"`
+	}
+)
+
+type (
+	AddDormitoryScopeRequest struct {
+		ActorId     string
+		UserId      string
+		DormitoryId string
+	}
+
+	RemoveDormitoryScopeRequest struct {
+		UserId      string
+		DormitoryId string
+	}
+)
@@ -0,0 +1,49 @@
+package requestmodels
+
+import (
+	"strconv"
+	"time"
+
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+type UserChange struct {
+	Cursor      string
+	UserId      string
+	DormitoryId string
+	Kind        string
+	OldValue    string
+	NewValue    string
+	CreatedAt   time.Time
+}
+
+func (*UserChange) From(msg *dbtypes.UserChange) *UserChange {
+	if msg == nil {
+		return nil
+	}
+
+	change := &UserChange{
+		Cursor:      strconv.FormatInt(msg.Id, 10),
+		UserId:      msg.UserId,
+		DormitoryId: msg.DormitoryId,
+		Kind:        msg.Kind,
+		CreatedAt:   msg.CreatedAt,
+	}
+
+	if msg.OldValue != nil {
+		change.OldValue = *msg.OldValue
+	}
+
+	if msg.NewValue != nil {
+		change.NewValue = *msg.NewValue
+	}
+
+	return change
+}
+
+// WatchUserChangesRequest starts after the change with Cursor, or at the
+// newest change when Cursor is empty.
+type WatchUserChangesRequest struct {
+	Cursor      string
+	DormitoryId string
+}
@@ -0,0 +1,180 @@
+package requestmodels
+
+import (
+	"encoding/json"
+	"time"
+
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+type Webhook struct {
+	Id          string `json:"id"`
+	DormitoryId string `json:"dormitory_id"`
+	URL         string `json:"url"`
+	// Secret signs the deliveries. It is only shown when the webhook is
+	// created and when the secret is rotated.
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedBy  *string   `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (*Webhook) From(msg *dbtypes.WebhookSubscription) *Webhook {
+	if msg == nil {
+		return nil
+	}
+
+	return &Webhook{
+		Id:          msg.Id,
+		DormitoryId: msg.DormitoryId,
+		URL:         msg.URL,
+		EventTypes:  msg.EventTypes,
+		Active:      msg.Active,
+		CreatedBy:   msg.CreatedBy,
+		CreatedAt:   msg.CreatedAt,
+	}
+}
+
+type CreateWebhookRequest struct {
+	ActorId     string `json:"-"`
+	DormitoryId string `json:"-"`
+
+	URL string `json:"url"`
+	// EventTypes limits the events sent to the webhook. Empty sends every
+	// event.
+	EventTypes []string `json:"event_types,omitempty"`
+}
+
+type (
+	ListWebhooksRequest struct {
+		DormitoryId string
+	}
+
+	ListWebhooksResponse struct {
+		Webhooks []*Webhook `json:"webhooks"`
+	}
+)
+
+type UpdateWebhookRequest struct {
+	DormitoryId string `json:"-"`
+	WebhookId   string `json:"-"`
+
+	URL *string `json:"url,omitempty"`
+	// EventTypes replaces the event types when set; an empty list sends
+	// every event.
+	EventTypes *[]string `json:"event_types,omitempty"`
+	// Active false pauses the webhook. Events of a paused webhook are not
+	// queued.
+	Active *bool `json:"active,omitempty"`
+}
+
+type RotateWebhookSecretRequest struct {
+	DormitoryId string
+	WebhookId   string
+}
+
+type DeleteWebhookRequest struct {
+	DormitoryId string
+	WebhookId   string
+}
+
+type WebhookDelivery struct {
+	Id        string          `json:"id"`
+	WebhookId string          `json:"webhook_id"`
+	EventId   string          `json:"event_id"`
+	EventType string          `json:"event_type"`
+	Event     json.RawMessage `json:"event" swaggertype:"object"`
+	Status    string          `json:"status"`
+	Attempts  int             `json:"attempts"`
+	// NextAttemptAt is set while the delivery is pending.
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LastError     *string    `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+func (*WebhookDelivery) From(msg *dbtypes.WebhookDelivery) *WebhookDelivery {
+	if msg == nil {
+		return nil
+	}
+
+	delivery := &WebhookDelivery{
+		Id:          msg.Id,
+		WebhookId:   msg.SubscriptionId,
+		EventId:     msg.EventId,
+		EventType:   msg.EventType,
+		Event:       msg.Body,
+		Status:      msg.Status,
+		Attempts:    msg.Attempts,
+		LastError:   msg.LastError,
+		CreatedAt:   msg.CreatedAt,
+		DeliveredAt: msg.DeliveredAt,
+	}
+
+	if msg.Status == dbtypes.WebhookDeliveryStatusPending {
+		nextAttemptAt := msg.NextAttemptAt
+		delivery.NextAttemptAt = &nextAttemptAt
+	}
+
+	return delivery
+}
+
+type (
+	// ListWebhookDeliveriesRequest is built from the query string. An empty
+	// Status lists deliveries in every status.
+	ListWebhookDeliveriesRequest struct {
+		DormitoryId string
+		WebhookId   string
+		Status      string
+		Limit       uint64
+		Offset      uint64
+	}
+
+	ListWebhookDeliveriesResponse struct {
+		Deliveries []*WebhookDelivery `json:"deliveries"`
+		Limit      uint64             `json:"limit"`
+		Offset     uint64             `json:"offset"`
+	}
+)
+
+type WebhookDeliveryAttempt struct {
+	Id int64 `json:"id"`
+	// StatusCode is missing when no answer came back.
+	StatusCode  *int      `json:"status_code,omitempty"`
+	Error       *string   `json:"error,omitempty"`
+	DurationMs  int64     `json:"duration_ms"`
+	AttemptedAt time.Time `json:"attempted_at"`
+}
+
+func (*WebhookDeliveryAttempt) From(msg *dbtypes.WebhookDeliveryAttempt) *WebhookDeliveryAttempt {
+	if msg == nil {
+		return nil
+	}
+
+	return &WebhookDeliveryAttempt{
+		Id:          msg.Id,
+		StatusCode:  msg.StatusCode,
+		Error:       msg.Error,
+		DurationMs:  msg.Duration.Milliseconds(),
+		AttemptedAt: msg.AttemptedAt,
+	}
+}
+
+type (
+	ListWebhookDeliveryAttemptsRequest struct {
+		DormitoryId string
+		WebhookId   string
+		DeliveryId  string
+	}
+
+	ListWebhookDeliveryAttemptsResponse struct {
+		Attempts []*WebhookDeliveryAttempt `json:"attempts"`
+	}
+)
+
+type RetryWebhookDeliveryRequest struct {
+	DormitoryId string
+	WebhookId   string
+	DeliveryId  string
+}
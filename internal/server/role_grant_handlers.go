@@ -0,0 +1,216 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+// @Summary Временная роль
+// @Description Выдает пользователю общежития администратора роль на срок. Пока срок действует, роль заменяет роль пользователя; по окончании срока сессии пользователя отзываются. Начало и конец срока записываются в журнал смены ролей. Последнему администратору общежития нельзя выдать другую роль, пользователю с ролью super_admin роль на срок не выдается
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор пользователя"
+// @Param request body rmodel.CreateRoleGrantRequest true "Роль и срок ее действия"
+// @Success 201 {object} rmodel.RoleGrant "Роль выдана"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав / пользователь имеет роль super_admin"
+// @Failure 404 {object} rmodel.ErrorResponse "Пользователь не найден"
+// @Failure 409 {object} rmodel.ErrorResponse "Нельзя понизить последнего администратора"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/role-grants [post]
+func (s *Server) createRoleGrantHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "createRoleGrantHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	var req rmodel.CreateRoleGrantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	if req.Role == "" || req.ValidUntil.IsZero() {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing role or valid_until")
+		return
+	}
+
+	req.ActorId = principal.UserId
+	req.DormitoryId = dormitoryId
+	req.UserId = userId
+
+	resp, err := s.authService.CreateRoleGrant(r.Context(), &req)
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("role granted",
+		slog.String("grant_id", resp.Id),
+		slog.String("user_id", resp.UserId),
+		slog.String("role", resp.Role),
+		slog.String("valid_until", resp.ValidUntil.Format(time.RFC3339)),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Временные роли пользователя
+// @Description Возвращает временные роли пользователя общежития администратора, новые первыми, включая завершенные
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор пользователя"
+// @Success 200 {object} rmodel.ListRoleGrantsResponse "Временные роли"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверный идентификатор пользователя"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/role-grants [get]
+func (s *Server) listRoleGrantsHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "listRoleGrantsHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	resp, err := s.authService.ListRoleGrants(r.Context(), &rmodel.ListRoleGrantsRequest{
+		DormitoryId: dormitoryId,
+		UserId:      userId,
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Завершение временной роли
+// @Description Завершает временную роль досрочно или отменяет ее, если срок еще не начался. Сессии пользователя отзываются при следующей проверке сроков
+// @Tags admin
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор пользователя"
+// @Param grant_id path string true "Идентификатор временной роли"
+// @Success 204 "Временная роль завершена"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверный идентификатор пользователя или временной роли"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Временная роль не найдена или уже завершена"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/role-grants/{grant_id} [delete]
+func (s *Server) endRoleGrantHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "endRoleGrantHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	grantId, ok := s.pathId(w, r, "grant_id", "grant")
+	if !ok {
+		return
+	}
+
+	err := s.authService.EndRoleGrant(r.Context(), &rmodel.EndRoleGrantRequest{
+		DormitoryId: dormitoryId,
+		UserId:      userId,
+		GrantId:     grantId,
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("role grant ended",
+		slog.String("grant_id", grantId),
+		slog.String("user_id", userId),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+// @Summary Общежития пользователя
+// @Description Возвращает общежития, в которых пользователь может действовать помимо своего. Доступно только super_admin
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Идентификатор пользователя"
+// @Success 200 {object} rmodel.DormitoryScopesResponse "Общежития пользователя"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверный идентификатор пользователя"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/dormitories [get]
+func (s *Server) listDormitoryScopesHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "listDormitoryScopesHandler"
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	resp, err := s.authService.ListDormitoryScopes(r.Context(), &rmodel.DormitoryScopesRequest{
+		UserId: userId,
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Добавление общежития пользователю
+// @Description Разрешает пользователю действовать в общежитии со своей ролью. Повторное добавление не является ошибкой. Доступно только super_admin
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Идентификатор пользователя"
+// @Param dormitory_id path string true "Идентификатор общежития"
+// @Success 200 {object} rmodel.DormitoryScopesResponse "Общежития пользователя"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверный идентификатор пользователя"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Пользователь или общежитие не найдены"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/dormitories/{dormitory_id} [put]
+func (s *Server) addDormitoryScopeHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "addDormitoryScopeHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	resp, err := s.authService.AddDormitoryScope(r.Context(), &rmodel.AddDormitoryScopeRequest{
+		ActorId:     principal.UserId,
+		UserId:      userId,
+		DormitoryId: r.PathValue("dormitory_id"),
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("dormitory scope added",
+		slog.String("user_id", resp.UserId),
+		slog.String("dormitory_id", r.PathValue("dormitory_id")),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Удаление общежития у пользователя
+// @Description Запрещает пользователю действовать в общежитии. Собственное общежитие пользователя не затрагивается. Доступно только super_admin
+// @Tags admin
+// @Security BearerAuth
+// @Param id path string true "Идентификатор пользователя"
+// @Param dormitory_id path string true "Идентификатор общежития"
+// @Success 204 "Общежитие удалено"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверный идентификатор пользователя"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Общежитие не было добавлено"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/users/{id}/dormitories/{dormitory_id} [delete]
+func (s *Server) removeDormitoryScopeHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "removeDormitoryScopeHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	userId, ok := s.pathId(w, r, "id", "user")
+	if !ok {
+		return
+	}
+
+	err := s.authService.RemoveDormitoryScope(r.Context(), &rmodel.RemoveDormitoryScopeRequest{
+		UserId:      userId,
+		DormitoryId: r.PathValue("dormitory_id"),
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("dormitory scope removed",
+		slog.String("user_id", userId),
+		slog.String("dormitory_id", r.PathValue("dormitory_id")),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
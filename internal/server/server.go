@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/dormitory-life/auth/internal/config"
+	"github.com/dormitory-life/auth/internal/constants"
 	auth "github.com/dormitory-life/auth/internal/service"
 
 	httpSwagger "github.com/swaggo/http-swagger"
@@ -21,6 +22,8 @@ type Server struct {
 	server      http.Server
 	authService auth.AuthServiceClient
 	logger      *slog.Logger
+
+	introspectionClients map[string]string
 }
 
 func New(cfg ServerConfig) *Server {
@@ -29,6 +32,7 @@ func New(cfg ServerConfig) *Server {
 	s.server.Handler = s.setupRouter()
 	s.authService = cfg.AuthService
 	s.logger = cfg.Logger
+	s.introspectionClients = cfg.Config.IntrospectionClients
 
 	return s
 }
@@ -39,6 +43,41 @@ func (s *Server) setupRouter() *http.ServeMux {
 	mux.HandleFunc("POST /auth/register", s.registerHandler)
 	mux.HandleFunc("POST /auth/login", s.loginHandler)
 	mux.HandleFunc("POST /auth/refresh", s.refreshHandler)
+	mux.HandleFunc("POST /auth/logout", s.logoutHandler)
+	mux.HandleFunc("POST /auth/logout-all", s.logoutAllHandler)
+	mux.HandleFunc("POST /auth/password/forgot", s.forgotPasswordHandler)
+	mux.HandleFunc("POST /auth/password/reset", s.resetPasswordHandler)
+	mux.Handle("POST /auth/password/change", s.authMiddleware(s.changePasswordHandler))
+	mux.HandleFunc("POST /auth/email/verify", s.verifyEmailHandler)
+	mux.HandleFunc("POST /auth/email/resend", s.resendVerificationEmailHandler)
+	mux.Handle("POST /auth/introspect", s.clientAuthMiddleware(s.introspectHandler))
+	mux.HandleFunc("GET /.well-known/jwks.json", s.jwksHandler)
+
+	adminRoles := []string{constants.UserAdminRole, constants.UserSuperAdminRole}
+	mux.Handle("GET /admin/users", s.authMiddleware(s.listUsersHandler, adminRoles...))
+	mux.Handle("GET /admin/users/{id}", s.authMiddleware(s.adminGetUserHandler, adminRoles...))
+	mux.Handle("PATCH /admin/users/{id}", s.authMiddleware(s.adminUpdateUserHandler, adminRoles...))
+	mux.Handle("PUT /admin/users/{id}/role", s.authMiddleware(s.changeUserRoleHandler, adminRoles...))
+	mux.Handle("GET /admin/role-changes", s.authMiddleware(s.listRoleChangesHandler, adminRoles...))
+	mux.Handle("POST /admin/users/{id}/role-grants", s.authMiddleware(s.createRoleGrantHandler, adminRoles...))
+	mux.Handle("GET /admin/users/{id}/role-grants", s.authMiddleware(s.listRoleGrantsHandler, adminRoles...))
+	mux.Handle("DELETE /admin/users/{id}/role-grants/{grant_id}", s.authMiddleware(s.endRoleGrantHandler, adminRoles...))
+	mux.Handle("GET /admin/permissions", s.authMiddleware(s.listPermissionsHandler, adminRoles...))
+	mux.Handle("GET /admin/users/{id}/permissions", s.authMiddleware(s.getUserPermissionsHandler, adminRoles...))
+	mux.Handle("PUT /admin/users/{id}/permissions/{permission}", s.authMiddleware(s.grantPermissionHandler, adminRoles...))
+	mux.Handle("DELETE /admin/users/{id}/permissions/{permission}", s.authMiddleware(s.revokePermissionHandler, adminRoles...))
+	mux.Handle("POST /admin/webhooks", s.authMiddleware(s.createWebhookHandler, adminRoles...))
+	mux.Handle("GET /admin/webhooks", s.authMiddleware(s.listWebhooksHandler, adminRoles...))
+	mux.Handle("PATCH /admin/webhooks/{id}", s.authMiddleware(s.updateWebhookHandler, adminRoles...))
+	mux.Handle("POST /admin/webhooks/{id}/rotate-secret", s.authMiddleware(s.rotateWebhookSecretHandler, adminRoles...))
+	mux.Handle("DELETE /admin/webhooks/{id}", s.authMiddleware(s.deleteWebhookHandler, adminRoles...))
+	mux.Handle("GET /admin/webhooks/{id}/deliveries", s.authMiddleware(s.listWebhookDeliveriesHandler, adminRoles...))
+	mux.Handle("GET /admin/webhooks/{id}/deliveries/{delivery_id}/attempts", s.authMiddleware(s.listWebhookDeliveryAttemptsHandler, adminRoles...))
+	mux.Handle("POST /admin/webhooks/{id}/deliveries/{delivery_id}/retry", s.authMiddleware(s.retryWebhookDeliveryHandler, adminRoles...))
+
+	mux.Handle("GET /admin/users/{id}/dormitories", s.authMiddleware(s.listDormitoryScopesHandler, constants.UserSuperAdminRole))
+	mux.Handle("PUT /admin/users/{id}/dormitories/{dormitory_id}", s.authMiddleware(s.addDormitoryScopeHandler, constants.UserSuperAdminRole))
+	mux.Handle("DELETE /admin/users/{id}/dormitories/{dormitory_id}", s.authMiddleware(s.removeDormitoryScopeHandler, constants.UserSuperAdminRole))
 
 	mux.Handle("GET /swagger/", httpSwagger.WrapHandler)
 
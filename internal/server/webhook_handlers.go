@@ -0,0 +1,480 @@
+package server
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+// @Summary Создание вебхука
+// @Description Подписывает URL на события пользователей общежития администратора: user.registered, user.logged_in, user.role_changed. Каждая доставка подписывается HMAC-SHA256 от "<X-Webhook-Timestamp>.<тело>" с секретом вебхука, подпись передается в заголовке X-Webhook-Signature как "sha256=<hex>". Секрет хранится в открытом виде, так как нужен для подписи, и возвращается только при создании и смене. Адреса внутренней сети (loopback, частные, link-local) запрещены, если они не разрешены в конфигурации
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param request body rmodel.CreateWebhookRequest true "URL и типы событий"
+// @Success 201 {object} rmodel.Webhook "Вебхук создан"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/webhooks [post]
+func (s *Server) createWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "createWebhookHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	var req rmodel.CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	if req.URL == "" {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, "Missing url")
+		return
+	}
+
+	req.ActorId = principal.UserId
+	req.DormitoryId = dormitoryId
+
+	resp, err := s.authService.CreateWebhook(r.Context(), &req)
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("webhook created",
+		slog.String("webhook_id", resp.Id),
+		slog.String("dormitory_id", dormitoryId),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Вебхуки общежития
+// @Description Возвращает вебхуки общежития администратора без секретов
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Success 200 {object} rmodel.ListWebhooksResponse "Вебхуки"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/webhooks [get]
+func (s *Server) listWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "listWebhooksHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	resp, err := s.authService.ListWebhooks(r.Context(), &rmodel.ListWebhooksRequest{
+		DormitoryId: dormitoryId,
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Изменение вебхука
+// @Description Меняет URL, типы событий или приостанавливает вебхук общежития администратора. События для приостановленного вебхука не ставятся в очередь
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор вебхука"
+// @Param request body rmodel.UpdateWebhookRequest true "Изменяемые поля"
+// @Success 200 {object} rmodel.Webhook "Вебхук изменен"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные данные / параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Вебхук не найден"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/webhooks/{id} [patch]
+func (s *Server) updateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "updateWebhookHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	var req rmodel.UpdateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeErrorResponse(w, err, http.StatusBadRequest)
+		s.logger.Error("error decoding request",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	req.DormitoryId = dormitoryId
+	req.WebhookId = r.PathValue("id")
+
+	resp, err := s.authService.UpdateWebhook(r.Context(), &req)
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("webhook updated",
+		slog.String("webhook_id", resp.Id),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Смена секрета вебхука
+// @Description Заменяет секрет вебхука общежития администратора новым и возвращает его. Еще не отправленные доставки, в том числе повторы, подписываются новым секретом
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор вебхука"
+// @Success 200 {object} rmodel.Webhook "Секрет заменен"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Вебхук не найден"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/webhooks/{id}/rotate-secret [post]
+func (s *Server) rotateWebhookSecretHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "rotateWebhookSecretHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	resp, err := s.authService.RotateWebhookSecret(r.Context(), &rmodel.RotateWebhookSecretRequest{
+		DormitoryId: dormitoryId,
+		WebhookId:   r.PathValue("id"),
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("webhook secret rotated",
+		slog.String("webhook_id", resp.Id),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Удаление вебхука
+// @Description Удаляет вебхук общежития администратора вместе с журналом его доставок
+// @Tags admin
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор вебхука"
+// @Success 204 "Вебхук удален"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Вебхук не найден"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/webhooks/{id} [delete]
+func (s *Server) deleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "deleteWebhookHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	err := s.authService.DeleteWebhook(r.Context(), &rmodel.DeleteWebhookRequest{
+		DormitoryId: dormitoryId,
+		WebhookId:   r.PathValue("id"),
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("webhook deleted",
+		slog.String("webhook_id", r.PathValue("id")),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Доставки вебхука
+// @Description Возвращает доставки событий вебхука, новые первыми. Доставка ожидает (pending), пока событие не доставлено (delivered) или не исчерпаны попытки (dead)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор вебхука"
+// @Param status query string false "Только доставки в этом статусе" Enums(pending, delivered, dead)
+// @Param limit query int false "Размер страницы (не больше 100)" default(20)
+// @Param offset query int false "Смещение" default(0)
+// @Success 200 {object} rmodel.ListWebhookDeliveriesResponse "Доставки"
+// @Failure 400 {object} rmodel.ErrorResponse "Неверные параметры запроса"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Вебхук не найден"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/webhooks/{id}/deliveries [get]
+func (s *Server) listWebhookDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "listWebhookDeliveriesHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	req := &rmodel.ListWebhookDeliveriesRequest{
+		DormitoryId: dormitoryId,
+		WebhookId:   r.PathValue("id"),
+		Status:      r.URL.Query().Get("status"),
+	}
+
+	if err := parsePagination(r.URL.Query(), &req.Limit, &req.Offset); err != nil {
+		writeErrorResponse(w, constants.ErrBadRequest, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp, err := s.authService.ListWebhookDeliveries(r.Context(), req)
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Попытки доставки
+// @Description Возвращает журнал попыток доставки события вебхука, старые первыми
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор вебхука"
+// @Param delivery_id path string true "Идентификатор доставки"
+// @Success 200 {object} rmodel.ListWebhookDeliveryAttemptsResponse "Попытки доставки"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Доставка не найдена"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/webhooks/{id}/deliveries/{delivery_id}/attempts [get]
+func (s *Server) listWebhookDeliveryAttemptsHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "listWebhookDeliveryAttemptsHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	resp, err := s.authService.ListWebhookDeliveryAttempts(r.Context(), &rmodel.ListWebhookDeliveryAttemptsRequest{
+		DormitoryId: dormitoryId,
+		WebhookId:   r.PathValue("id"),
+		DeliveryId:  r.PathValue("delivery_id"),
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		s.logger.Error("error encoding response",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+	}
+}
+
+// @Summary Повтор доставки
+// @Description Возвращает доставку, исчерпавшую попытки (dead), в очередь с новым набором попыток
+// @Tags admin
+// @Security BearerAuth
+// @Param dormitory_id query string false "Общежитие, по умолчанию общежитие администратора"
+// @Param id path string true "Идентификатор вебхука"
+// @Param delivery_id path string true "Идентификатор доставки"
+// @Success 204 "Доставка поставлена в очередь"
+// @Failure 401 {object} rmodel.ErrorResponse "Access-токен недействителен"
+// @Failure 403 {object} rmodel.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} rmodel.ErrorResponse "Доставка не найдена"
+// @Failure 409 {object} rmodel.ErrorResponse "Доставка не в статусе dead"
+// @Failure 500 {object} rmodel.ErrorResponse "Внутренняя ошибка сервера"
+// @Router /admin/webhooks/{id}/deliveries/{delivery_id}/retry [post]
+func (s *Server) retryWebhookDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	const handlerName = "retryWebhookDeliveryHandler"
+
+	principal, ok := PrincipalFromContext(r.Context())
+	if !ok {
+		writeErrorResponse(w, constants.ErrUnauthorized, http.StatusUnauthorized)
+		return
+	}
+
+	dormitoryId, ok := s.adminDormitoryId(w, r, principal)
+	if !ok {
+		return
+	}
+
+	err := s.authService.RetryWebhookDelivery(r.Context(), &rmodel.RetryWebhookDeliveryRequest{
+		DormitoryId: dormitoryId,
+		WebhookId:   r.PathValue("id"),
+		DeliveryId:  r.PathValue("delivery_id"),
+	})
+	if err != nil {
+		s.handleError(w, err)
+		s.logger.Error("error",
+			slog.String("error", err.Error()),
+			slog.String("handler", handlerName),
+		)
+
+		return
+	}
+
+	s.logger.Info("webhook delivery retried",
+		slog.String("webhook_id", r.PathValue("id")),
+		slog.String("delivery_id", r.PathValue("delivery_id")),
+		slog.String("actor_id", principal.UserId),
+	)
+
+	w.WriteHeader(http.StatusNoContent)
+}
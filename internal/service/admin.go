@@ -0,0 +1,206 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+const (
+	defaultUsersPageSize uint64 = 20
+	maxUsersPageSize     uint64 = 100
+)
+
+func isKnownRole(role string) bool {
+	return slices.Contains(constants.UserRoles, role)
+}
+
+func (s *AuthService) ListUsers(
+	ctx context.Context,
+	request *rmodel.ListUsersRequest,
+) (*rmodel.ListUsersResponse, error) {
+	if request == nil || request.DormitoryId == "" {
+		return nil, ErrBadRequest
+	}
+
+	if request.Role != "" && !isKnownRole(request.Role) {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrBadRequest, request.Role)
+	}
+
+	if request.Status != "" && !knownStatuses[request.Status] {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrBadRequest, request.Status)
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultUsersPageSize
+	}
+
+	if limit > maxUsersPageSize {
+		limit = maxUsersPageSize
+	}
+
+	dbRequest := &dbtypes.ListUsersRequest{
+		DormitoryId:   request.DormitoryId,
+		Role:          request.Role,
+		Status:        request.Status,
+		EmailContains: request.EmailContains,
+		SortBy:        request.SortBy,
+		SortDesc:      request.SortDesc,
+		Limit:         limit,
+		Offset:        request.Offset,
+	}
+
+	if request.CreatedFrom != nil {
+		createdFrom := request.CreatedFrom.UTC()
+		dbRequest.CreatedFrom = &createdFrom
+	}
+
+	if request.CreatedTo != nil {
+		createdTo := request.CreatedTo.UTC()
+		dbRequest.CreatedTo = &createdTo
+	}
+
+	resp, err := s.repository.ListUsers(ctx, dbRequest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error listing users: %v", s.handleDBError(err), err)
+	}
+
+	result := &rmodel.ListUsersResponse{
+		Users:  make([]*rmodel.AdminUser, 0, len(resp.Users)),
+		Total:  resp.Total,
+		Limit:  limit,
+		Offset: request.Offset,
+	}
+
+	for i := range resp.Users {
+		result.Users = append(result.Users, new(rmodel.AdminUser).From(&resp.Users[i]))
+	}
+
+	return result, nil
+}
+
+func (s *AuthService) AdminGetUser(
+	ctx context.Context,
+	request *rmodel.AdminGetUserRequest,
+) (*rmodel.AdminUser, error) {
+	if request == nil || request.DormitoryId == "" || request.UserId == "" {
+		return nil, ErrBadRequest
+	}
+
+	resp, err := s.repository.GetUserById(ctx, &dbtypes.GetUserInfoByIdRequest{
+		Id: request.UserId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error getting user: %v", s.handleDBError(err), err)
+	}
+
+	// users of other dormitories are indistinguishable from missing ones
+	if resp.DormitoryId != request.DormitoryId {
+		return nil, fmt.Errorf("%w: user not found", ErrNotFound)
+	}
+
+	return &rmodel.AdminUser{
+		UserId:          resp.UserId,
+		Email:           resp.Email,
+		DormitoryId:     resp.DormitoryId,
+		Role:            resp.Role,
+		CreatedAt:       resp.CreatedAt,
+		EmailVerifiedAt: resp.EmailVerifiedAt,
+		Status:          resp.Status,
+		StatusReason:    resp.StatusReason,
+		StatusExpiresAt: resp.StatusExpiresAt,
+	}, nil
+}
+
+func (s *AuthService) AdminUpdateUser(
+	ctx context.Context,
+	request *rmodel.AdminUpdateUserRequest,
+) (*rmodel.AdminUser, error) {
+	if request == nil || request.DormitoryId == "" || request.UserId == "" {
+		return nil, ErrBadRequest
+	}
+
+	if request.Role == nil && request.Status == nil {
+		return nil, fmt.Errorf("%w: nothing to update", ErrBadRequest)
+	}
+
+	if request.Role != nil && !isKnownRole(*request.Role) {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrBadRequest, *request.Role)
+	}
+
+	if request.Status != nil {
+		if err := validateStatusUpdate(request); err != nil {
+			return nil, err
+		}
+	}
+
+	// both changes go in one transaction, a refused one leaves the other undone
+	updateRequest := &dbtypes.UpdateUserRequest{}
+
+	if request.Role != nil {
+		if *request.Role == constants.UserSuperAdminRole {
+			return nil, fmt.Errorf("%w: the super admin role can only be given with authctl", ErrForbidden)
+		}
+
+		updateRequest.Role = &dbtypes.UpdateUserRoleRequest{
+			UserId:      request.UserId,
+			DormitoryId: request.DormitoryId,
+			Role:        *request.Role,
+			ActorId:     request.ActorId,
+			ActorSource: dbtypes.RoleChangeSourceAPI,
+		}
+	}
+
+	if request.Status != nil {
+		updateRequest.Status = &dbtypes.UpdateUserStatusRequest{
+			UserId:      request.UserId,
+			DormitoryId: request.DormitoryId,
+			Status:      *request.Status,
+		}
+
+		// an active user carries no reason or expiry
+		if *request.Status != constants.UserStatusActive {
+			updateRequest.Status.Reason = request.StatusReason
+
+			if request.StatusExpiresAt != nil {
+				expiresAt := request.StatusExpiresAt.UTC()
+				updateRequest.Status.ExpiresAt = &expiresAt
+			}
+		}
+	}
+
+	resp, err := s.repository.UpdateUser(ctx, updateRequest)
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: user not found", ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error updating user: %v", s.handleDBError(err), err)
+	}
+
+	return new(rmodel.AdminUser).From(resp.User), nil
+}
+
+func validateStatusUpdate(request *rmodel.AdminUpdateUserRequest) error {
+	if !knownStatuses[*request.Status] {
+		return fmt.Errorf("%w: unknown status %q", ErrBadRequest, *request.Status)
+	}
+
+	if request.UserId == request.ActorId {
+		return fmt.Errorf("%w: admins cannot change their own status", ErrBadRequest)
+	}
+
+	if request.StatusExpiresAt != nil && !request.StatusExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: status_expires_at must be in the future", ErrBadRequest)
+	}
+
+	return nil
+}
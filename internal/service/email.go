@@ -0,0 +1,95 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/dormitory-life/auth/internal/notifier"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+func (s *AuthService) VerifyEmail(
+	ctx context.Context,
+	request *rmodel.VerifyEmailRequest,
+) error {
+	if request == nil || request.Token == "" {
+		return ErrBadRequest
+	}
+
+	_, err := s.repository.VerifyEmail(ctx, &dbtypes.VerifyEmailRequest{
+		TokenHash: hashToken(request.Token),
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return fmt.Errorf("%w: invalid or expired verification token", ErrBadRequest)
+		}
+
+		return fmt.Errorf("%w: error verifying email: %v", s.handleDBError(err), err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) ResendVerificationEmail(
+	ctx context.Context,
+	request *rmodel.ResendVerificationEmailRequest,
+) error {
+	if request == nil || request.Email == "" {
+		return ErrBadRequest
+	}
+
+	user, err := s.repository.GetUserByEmail(ctx, &dbtypes.GetUserByEmailRequest{
+		Email: request.Email,
+	})
+	if err != nil {
+		// the response must not reveal whether the email is registered
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: error getting user while resending verification email: %v", s.handleDBError(err), err)
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	return s.sendEmailVerification(ctx, user.UserId, user.Email)
+}
+
+func (s *AuthService) sendEmailVerification(
+	ctx context.Context,
+	userId string,
+	email string,
+) error {
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().UTC().Add(s.emailVerificationTTL)
+
+	err = s.repository.CreateEmailVerificationToken(ctx, &dbtypes.CreateEmailVerificationTokenRequest{
+		UserId:    userId,
+		TokenHash: hashToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: error saving email verification token: %v", s.handleDBError(err), err)
+	}
+
+	err = s.notifier.SendEmailVerification(ctx, &notifier.EmailVerificationMessage{
+		Email:     email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: error sending email verification: %v", ErrInternal, err)
+	}
+
+	return nil
+}
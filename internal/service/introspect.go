@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+func (s *AuthService) IntrospectToken(
+	ctx context.Context,
+	request *rmodel.IntrospectRequest,
+) (*rmodel.IntrospectResponse, error) {
+	if request == nil {
+		return nil, ErrBadRequest
+	}
+
+	inactive := &rmodel.IntrospectResponse{
+		Active: false,
+	}
+
+	claims, err := s.authenticate(ctx, request.Token)
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			return inactive, nil
+		}
+
+		return nil, err
+	}
+
+	user, err := s.repository.GetUserById(ctx, &dbtypes.GetUserInfoByIdRequest{
+		Id: claims.UserId,
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return inactive, nil
+		}
+
+		return nil, fmt.Errorf("%w: error getting user while introspecting token: %v", s.handleDBError(err), err)
+	}
+
+	if checkUserActive(user.Status, user.StatusExpiresAt) != nil {
+		return inactive, nil
+	}
+
+	dormitories, err := s.dormitoryScopes(ctx, user.UserId, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &rmodel.IntrospectResponse{
+		Active:      true,
+		Subject:     user.UserId,
+		UserId:      user.UserId,
+		DormitoryId: user.DormitoryId,
+		Dormitories: dormitories,
+		Role:        user.Role,
+		SessionId:   claims.SessionId,
+		ClientId:    claims.ClientId,
+		TokenType:   "access",
+		Issuer:      claims.Issuer,
+		Audience:    claims.Audience,
+		JwtId:       claims.ID,
+	}
+
+	if claims.ExpiresAt != nil {
+		result.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+
+	if claims.IssuedAt != nil {
+		result.IssuedAt = claims.IssuedAt.Unix()
+	}
+
+	return result, nil
+}
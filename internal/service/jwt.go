@@ -5,39 +5,152 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/dormitory-life/auth/internal/jwtkeys"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+	"github.com/dormitory-life/auth/pkg/authverify"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// tokenClaims is shared with pkg/authverify, so other services parse exactly
+// what is issued here.
+type tokenClaims = authverify.Claims
+
+type tokenSubject struct {
+	userId      string
+	dormitoryId string
+	dormitories []string
+	role        string
+	sessionId   string
+	clientId    string
+}
+
+type jwtTokens struct {
+	accessToken      string
+	refreshToken     string
+	refreshExpiresAt time.Time
+}
+
 func (s *AuthService) generateJWTTokens(
 	ctx context.Context,
-	userId string,
-	dormitoryId string,
-) (string, string, error) {
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":      userId,
-		"dormitory_id": dormitoryId,
-		"exp":          time.Now().Add(15 * time.Minute).Unix(),
-		"iat":          time.Now().Unix(),
-		"type":         "access",
+	subject *tokenSubject,
+) (*jwtTokens, error) {
+	lifetime := s.tokenLifetime(subject.clientId)
+
+	// refresh_tokens.expires_at has no time zone and is compared with UTC
+	now := time.Now().UTC()
+	refreshExpiresAt := now.Add(lifetime.refreshTokenTTL)
+
+	signingKey := s.keyring.Active()
+
+	accessToken := newJWTToken(signingKey, &tokenClaims{
+		UserId:      subject.userId,
+		DormitoryId: subject.dormitoryId,
+		Dormitories: subject.dormitories,
+		Role:        subject.role,
+		SessionId:   subject.sessionId,
+		ClientId:    subject.clientId,
+		Type:        authverify.TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   subject.userId,
+			Audience:  s.audience,
+			ExpiresAt: jwt.NewNumericDate(now.Add(lifetime.accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        uuid.NewString(),
+		},
 	})
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":      userId,
-		"dormitory_id": dormitoryId,
-		"exp":          time.Now().Add(7 * 24 * time.Hour).Unix(),
-		"iat":          time.Now().Unix(),
-		"type":         "refresh",
+	refreshToken := newJWTToken(signingKey, &tokenClaims{
+		UserId:      subject.userId,
+		DormitoryId: subject.dormitoryId,
+		SessionId:   subject.sessionId,
+		ClientId:    subject.clientId,
+		Type:        authverify.TokenTypeRefresh,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   subject.userId,
+			ExpiresAt: jwt.NewNumericDate(refreshExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        uuid.NewString(),
+		},
 	})
 
-	accessTokenString, err := accessToken.SignedString([]byte(s.jwtSecret))
+	accessTokenString, err := accessToken.SignedString(signingKey.SigningKey)
 	if err != nil {
-		return "", "", fmt.Errorf("%w: error while generating access token: %v", ErrInternal, err)
+		return nil, fmt.Errorf("%w: error while generating access token: %v", ErrInternal, err)
 	}
 
-	refreshTokenString, err := refreshToken.SignedString([]byte(s.jwtSecret))
+	refreshTokenString, err := refreshToken.SignedString(signingKey.SigningKey)
 	if err != nil {
-		return "", "", fmt.Errorf("%w: error while generating refresh token: %v", ErrInternal, err)
+		return nil, fmt.Errorf("%w: error while generating refresh token: %v", ErrInternal, err)
+	}
+
+	return &jwtTokens{
+		accessToken:      accessTokenString,
+		refreshToken:     refreshTokenString,
+		refreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func newJWTToken(signingKey *jwtkeys.Key, claims jwt.Claims) *jwt.Token {
+	token := jwt.NewWithClaims(signingKey.Method, claims)
+	if signingKey.Id != "" {
+		token.Header["kid"] = signingKey.Id
+	}
+
+	return token
+}
+
+func (s *AuthService) parseJWTToken(
+	tokenString string,
+	tokenType string,
+) (*tokenClaims, error) {
+	options := []jwt.ParserOption{jwt.WithExpirationRequired()}
+
+	// refresh tokens are checked against the refresh token store, so only
+	// access tokens are bound to the configured issuer and audience
+	if tokenType == "access" {
+		if s.issuer != "" {
+			options = append(options, jwt.WithIssuer(s.issuer))
+		}
+
+		if len(s.audience) > 0 {
+			options = append(options, jwt.WithAudience(s.audience...))
+		}
 	}
 
-	return accessTokenString, refreshTokenString, nil
+	claims := &tokenClaims{}
+
+	_, err := jwt.ParseWithClaims(tokenString, claims, s.verificationKey, options...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid token: %v", ErrUnauthorized, err)
+	}
+
+	if claims.Type != tokenType {
+		return nil, fmt.Errorf("%w: unexpected token type", ErrUnauthorized)
+	}
+
+	return claims, nil
+}
+
+func (s *AuthService) verificationKey(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, err := s.keyring.Lookup(kid, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), kid)
+	}
+
+	return key.VerifyKey, nil
+}
+
+func (s *AuthService) GetJWKS(ctx context.Context) (*rmodel.JWKSResponse, error) {
+	keySet := s.keyring.JWKS(time.Now())
+
+	return new(rmodel.JWKSResponse).From(&keySet), nil
 }
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/config"
+	"github.com/dormitory-life/auth/internal/constants"
+)
+
+type tokenLifetime struct {
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+}
+
+func newTokenLifetimes(
+	accessTokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+	clients map[string]config.TokenLifetimeConfig,
+) (tokenLifetime, map[string]tokenLifetime) {
+	defaults := tokenLifetime{
+		accessTokenTTL:  accessTokenTTL,
+		refreshTokenTTL: refreshTokenTTL,
+	}
+
+	if defaults.accessTokenTTL <= 0 {
+		defaults.accessTokenTTL = constants.AccessTokenTTL
+	}
+
+	if defaults.refreshTokenTTL <= 0 {
+		defaults.refreshTokenTTL = constants.RefreshTokenTTL
+	}
+
+	profiles := make(map[string]tokenLifetime, len(clients))
+	for clientId, client := range clients {
+		profile := defaults
+
+		if client.AccessTokenTTL > 0 {
+			profile.accessTokenTTL = client.AccessTokenTTL
+		}
+
+		if client.RefreshTokenTTL > 0 {
+			profile.refreshTokenTTL = client.RefreshTokenTTL
+		}
+
+		profiles[clientId] = profile
+	}
+
+	return defaults, profiles
+}
+
+func (s *AuthService) validateClientId(clientId string) error {
+	if clientId == "" {
+		return nil
+	}
+
+	if _, ok := s.clientLifetimes[clientId]; !ok {
+		return fmt.Errorf("%w: unknown client_id %q", ErrBadRequest, clientId)
+	}
+
+	return nil
+}
+
+// tokenLifetime falls back to the default lifetimes, so sessions of a client
+// whose profile was removed from the config keep working.
+func (s *AuthService) tokenLifetime(clientId string) tokenLifetime {
+	if profile, ok := s.clientLifetimes[clientId]; ok {
+		return profile
+	}
+
+	return s.defaultLifetime
+}
@@ -0,0 +1,150 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/dormitory-life/auth/internal/notifier"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func (s *AuthService) ForgotPassword(
+	ctx context.Context,
+	request *rmodel.ForgotPasswordRequest,
+) error {
+	if request == nil || request.Email == "" {
+		return ErrBadRequest
+	}
+
+	user, err := s.repository.GetUserByEmail(ctx, &dbtypes.GetUserByEmailRequest{
+		Email: request.Email,
+	})
+	if err != nil {
+		// the response must not reveal whether the email is registered
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: error getting user while requesting password reset: %v", s.handleDBError(err), err)
+	}
+
+	token, err := generateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().UTC().Add(s.passwordResetTTL)
+
+	err = s.repository.CreatePasswordResetToken(ctx, &dbtypes.CreatePasswordResetTokenRequest{
+		UserId:    user.UserId,
+		TokenHash: hashToken(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: error saving password reset token: %v", s.handleDBError(err), err)
+	}
+
+	err = s.notifier.SendPasswordReset(ctx, &notifier.PasswordResetMessage{
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+	// failing only for registered emails would reveal them, the user can
+	// ask again
+	if err != nil {
+		s.logger.Error("error sending password reset",
+			slog.String("error", err.Error()),
+			slog.String("user_id", user.UserId),
+		)
+	}
+
+	return nil
+}
+
+func (s *AuthService) ResetPassword(
+	ctx context.Context,
+	request *rmodel.ResetPasswordRequest,
+) error {
+	if request == nil || request.Token == "" || request.NewPassword == "" {
+		return ErrBadRequest
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%w: error hashing password: %v", ErrInternal, err)
+	}
+
+	_, err = s.repository.ResetPassword(ctx, &dbtypes.ResetPasswordRequest{
+		TokenHash: hashToken(request.Token),
+		Password:  string(hashedPassword),
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return fmt.Errorf("%w: invalid or expired reset token", ErrBadRequest)
+		}
+
+		return fmt.Errorf("%w: error resetting password: %v", s.handleDBError(err), err)
+	}
+
+	return nil
+}
+
+func generateOpaqueToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("%w: error generating token: %v", ErrInternal, err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func (s *AuthService) ChangePassword(
+	ctx context.Context,
+	request *rmodel.ChangePasswordRequest,
+) error {
+	if request == nil || request.UserId == "" || request.CurrentPassword == "" || request.NewPassword == "" {
+		return ErrBadRequest
+	}
+
+	resp, err := s.repository.GetUserPassword(ctx, &dbtypes.GetUserPasswordRequest{
+		UserId: request.UserId,
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return fmt.Errorf("%w: user not found", ErrUnauthorized)
+		}
+
+		return fmt.Errorf("%w: error getting user while changing password: %v", s.handleDBError(err), err)
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(resp.Password), []byte(request.CurrentPassword))
+	if err != nil {
+		return fmt.Errorf("%w: incorrect current password", ErrForbidden)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%w: error hashing password: %v", ErrInternal, err)
+	}
+
+	err = s.repository.UpdatePassword(ctx, &dbtypes.UpdatePasswordRequest{
+		UserId:              request.UserId,
+		Password:            string(hashedPassword),
+		RevokeOtherSessions: request.RevokeOtherSessions,
+		KeepFamilyId:        request.SessionId,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: error updating password: %v", s.handleDBError(err), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,348 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+	"github.com/google/uuid"
+)
+
+// maxBatchChecks bounds BatchCheckPermission so one call cannot load an
+// unbounded number of users.
+const maxBatchChecks = 500
+
+// CheckPermission decides whether the user may do something that needs the
+// permission inside the dormitory. A denial is not an error, the response
+// carries the reason.
+func (s *AuthService) CheckPermission(
+	ctx context.Context,
+	request *rmodel.CheckPermissionRequest,
+) (*rmodel.CheckPermissionResponse, error) {
+	if request == nil || request.UserId == "" || request.Permission == "" {
+		return nil, ErrBadRequest
+	}
+
+	if err := checkUserId(request.UserId); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repository.GetUserById(ctx, &dbtypes.GetUserInfoByIdRequest{
+		Id: request.UserId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error getting user by id: %v", s.handleDBError(err), err)
+	}
+
+	sets, err := s.repository.GetPermissionSets(ctx, &dbtypes.GetPermissionSetsRequest{
+		UserIds:     []string{user.UserId},
+		Roles:       []string{user.Role},
+		Permissions: []string{request.Permission},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error getting permission sets: %v", s.handleDBError(err), err)
+	}
+
+	return checkUserPermission(&dbtypes.User{
+		UserId:          user.UserId,
+		DormitoryId:     user.DormitoryId,
+		Role:            user.Role,
+		Status:          user.Status,
+		StatusExpiresAt: user.StatusExpiresAt,
+	}, sets, request.DormitoryId, request.Permission, time.Now().UTC()), nil
+}
+
+// BatchCheckPermission makes many checks with the users, their permissions
+// and scopes loaded in a few queries. Results follow the order of the checks;
+// a check of a missing user is denied instead of failing the whole batch.
+func (s *AuthService) BatchCheckPermission(
+	ctx context.Context,
+	request *rmodel.BatchCheckPermissionRequest,
+) (*rmodel.BatchCheckPermissionResponse, error) {
+	if request == nil || len(request.Checks) == 0 {
+		return nil, ErrBadRequest
+	}
+
+	if len(request.Checks) > maxBatchChecks {
+		return nil, fmt.Errorf("%w: at most %d checks per batch", ErrBadRequest, maxBatchChecks)
+	}
+
+	var (
+		ids         []string
+		permissions []string
+
+		seenIds         = make(map[string]bool, len(request.Checks))
+		seenPermissions = make(map[string]bool)
+	)
+
+	for _, check := range request.Checks {
+		if check.UserId == "" || check.Permission == "" {
+			return nil, ErrBadRequest
+		}
+
+		// a malformed id cannot match a user and would fail the query of
+		// the whole batch, its checks are denied below
+		if _, err := uuid.Parse(check.UserId); err == nil && !seenIds[check.UserId] {
+			seenIds[check.UserId] = true
+			ids = append(ids, check.UserId)
+		}
+
+		if !seenPermissions[check.Permission] {
+			seenPermissions[check.Permission] = true
+			permissions = append(permissions, check.Permission)
+		}
+	}
+
+	users := make(map[string]*dbtypes.User, len(ids))
+	if len(ids) > 0 {
+		resp, err := s.repository.GetUsersByIds(ctx, &dbtypes.GetUsersByIdsRequest{
+			Ids: ids,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: error getting users by ids: %v", s.handleDBError(err), err)
+		}
+
+		for i := range resp.Users {
+			users[resp.Users[i].UserId] = &resp.Users[i]
+		}
+	}
+
+	var (
+		userIds   = make([]string, 0, len(users))
+		roles     []string
+		seenRoles = make(map[string]bool)
+	)
+
+	for _, user := range users {
+		userIds = append(userIds, user.UserId)
+
+		if !seenRoles[user.Role] {
+			seenRoles[user.Role] = true
+			roles = append(roles, user.Role)
+		}
+	}
+
+	sets, err := s.repository.GetPermissionSets(ctx, &dbtypes.GetPermissionSetsRequest{
+		UserIds:     userIds,
+		Roles:       roles,
+		Permissions: permissions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error getting permission sets: %v", s.handleDBError(err), err)
+	}
+
+	var (
+		now = time.Now().UTC()
+
+		// the same check repeated in a batch is answered once
+		decisions = make(map[rmodel.CheckPermissionRequest]*rmodel.CheckPermissionResponse)
+	)
+
+	result := &rmodel.BatchCheckPermissionResponse{
+		Results: make([]*rmodel.CheckPermissionResponse, 0, len(request.Checks)),
+	}
+
+	for _, check := range request.Checks {
+		decision, ok := decisions[check]
+		if !ok {
+			user, found := users[check.UserId]
+			if !found {
+				decision = &rmodel.CheckPermissionResponse{
+					Reason:       "User not found",
+					DenialReason: constants.DenialReasonUserNotFound,
+				}
+			} else {
+				decision = checkUserPermission(user, sets, check.DormitoryId, check.Permission, now)
+			}
+
+			decisions[check] = decision
+		}
+
+		result.Results = append(result.Results, decision)
+	}
+
+	return result, nil
+}
+
+// checkUserPermission decides a check from the sets loaded for the user.
+func checkUserPermission(
+	user *dbtypes.User,
+	sets *dbtypes.GetPermissionSetsResponse,
+	dormitoryId string,
+	permission string,
+	now time.Time,
+) *rmodel.CheckPermissionResponse {
+	// staff may act in the dormitories of their scopes, super admins in all
+	if user.DormitoryId != dormitoryId && user.Role != constants.UserSuperAdminRole &&
+		!sets.Scopes[user.UserId][dormitoryId] {
+		return &rmodel.CheckPermissionResponse{
+			Reason:       "Permission for another dormitory is denied",
+			UserRole:     user.Role,
+			DenialReason: constants.DenialReasonOtherDormitory,
+		}
+	}
+
+	status := effectiveStatus(user.Status, user.StatusExpiresAt, now)
+	if status != constants.UserStatusActive {
+		return &rmodel.CheckPermissionResponse{
+			Reason:       fmt.Sprintf("User account is '%s'", status),
+			UserRole:     user.Role,
+			DenialReason: constants.DenialReasonAccountInactive,
+		}
+	}
+
+	// super admins hold every permission, including ones added later
+	if user.Role != constants.UserSuperAdminRole &&
+		!sets.RolePermissions[user.Role][permission] && !sets.Grants[user.UserId][permission] {
+		return &rmodel.CheckPermissionResponse{
+			Reason:       fmt.Sprintf("User role is '%s', permission '%s' is not granted", user.Role, permission),
+			UserRole:     user.Role,
+			DenialReason: constants.DenialReasonPermissionNotGranted,
+		}
+	}
+
+	return &rmodel.CheckPermissionResponse{
+		Allowed:  true,
+		Reason:   "Allowed",
+		UserRole: user.Role,
+	}
+}
+
+func (s *AuthService) ListPermissions(ctx context.Context) (*rmodel.ListPermissionsResponse, error) {
+	resp, err := s.repository.ListPermissions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error listing permissions: %v", s.handleDBError(err), err)
+	}
+
+	result := &rmodel.ListPermissionsResponse{
+		Permissions: make([]*rmodel.Permission, 0, len(resp.Permissions)),
+	}
+
+	for i := range resp.Permissions {
+		result.Permissions = append(result.Permissions, new(rmodel.Permission).From(&resp.Permissions[i]))
+	}
+
+	return result, nil
+}
+
+func (s *AuthService) GetUserPermissions(
+	ctx context.Context,
+	request *rmodel.UserPermissionsRequest,
+) (*rmodel.UserPermissionsResponse, error) {
+	if request == nil || request.DormitoryId == "" || request.UserId == "" {
+		return nil, ErrBadRequest
+	}
+
+	user, err := s.repository.GetUserById(ctx, &dbtypes.GetUserInfoByIdRequest{
+		Id: request.UserId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error getting user: %v", s.handleDBError(err), err)
+	}
+
+	// users of other dormitories are indistinguishable from missing ones
+	if user.DormitoryId != request.DormitoryId {
+		return nil, fmt.Errorf("%w: user not found", ErrNotFound)
+	}
+
+	resp, err := s.repository.GetUserPermissions(ctx, &dbtypes.GetUserPermissionsRequest{
+		UserId: user.UserId,
+		Role:   user.Role,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error getting user permissions: %v", s.handleDBError(err), err)
+	}
+
+	result := &rmodel.UserPermissionsResponse{
+		UserId:          user.UserId,
+		Role:            user.Role,
+		RolePermissions: resp.RolePermissions,
+		Grants:          make([]*rmodel.PermissionGrant, 0, len(resp.Grants)),
+	}
+
+	// super admins hold every permission without role_permissions rows
+	if user.Role == constants.UserSuperAdminRole {
+		permissions, err := s.repository.ListPermissions(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("%w: error listing permissions: %v", s.handleDBError(err), err)
+		}
+
+		result.RolePermissions = make([]string, 0, len(permissions.Permissions))
+		for _, permission := range permissions.Permissions {
+			result.RolePermissions = append(result.RolePermissions, permission.Name)
+		}
+	}
+
+	if result.RolePermissions == nil {
+		result.RolePermissions = []string{}
+	}
+
+	for i := range resp.Grants {
+		result.Grants = append(result.Grants, new(rmodel.PermissionGrant).From(&resp.Grants[i]))
+	}
+
+	return result, nil
+}
+
+func (s *AuthService) GrantPermission(
+	ctx context.Context,
+	request *rmodel.GrantPermissionRequest,
+) (*rmodel.UserPermissionsResponse, error) {
+	if request == nil || request.DormitoryId == "" || request.UserId == "" || request.Permission == "" {
+		return nil, ErrBadRequest
+	}
+
+	dbRequest := &dbtypes.GrantPermissionRequest{
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+		Permission:  request.Permission,
+		GrantedBy:   request.ActorId,
+	}
+
+	if request.ValidFrom != nil {
+		validFrom := request.ValidFrom.UTC()
+		dbRequest.ValidFrom = &validFrom
+	}
+
+	if request.ValidUntil != nil {
+		validUntil := request.ValidUntil.UTC()
+		dbRequest.ValidUntil = &validUntil
+	}
+
+	if err := validateGrantPeriod(dbRequest.ValidFrom, dbRequest.ValidUntil, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	err := s.repository.GrantPermission(ctx, dbRequest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error granting permission: %v", s.handleDBError(err), err)
+	}
+
+	return s.GetUserPermissions(ctx, &rmodel.UserPermissionsRequest{
+		DormitoryId: request.DormitoryId,
+		UserId:      request.UserId,
+	})
+}
+
+func (s *AuthService) RevokePermission(
+	ctx context.Context,
+	request *rmodel.RevokePermissionRequest,
+) error {
+	if request == nil || request.DormitoryId == "" || request.UserId == "" || request.Permission == "" {
+		return ErrBadRequest
+	}
+
+	err := s.repository.RevokePermission(ctx, &dbtypes.RevokePermissionRequest{
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+		Permission:  request.Permission,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: error revoking permission: %v", s.handleDBError(err), err)
+	}
+
+	return nil
+}
@@ -0,0 +1,216 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+	"github.com/google/uuid"
+)
+
+const testPermission = "laundry.book"
+
+func TestCheckPermission(t *testing.T) {
+	past := time.Now().UTC().Add(-time.Hour)
+	future := time.Now().UTC().Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		role        string
+		dormitoryId string
+		setup       func(repo *fakeRepository, user *dbtypes.User)
+		wantAllowed bool
+		wantReason  string
+	}{
+		{
+			name:        "role permission",
+			role:        constants.UserStudentRole,
+			dormitoryId: "01",
+			wantAllowed: true,
+		},
+		{
+			name:        "not granted",
+			role:        constants.UserMaintenanceRole,
+			dormitoryId: "01",
+			wantReason:  constants.DenialReasonPermissionNotGranted,
+		},
+		{
+			name:        "user grant",
+			role:        constants.UserMaintenanceRole,
+			dormitoryId: "01",
+			setup: func(repo *fakeRepository, user *dbtypes.User) {
+				repo.grants[user.UserId] = map[string]bool{testPermission: true}
+			},
+			wantAllowed: true,
+		},
+		{
+			name:        "other dormitory",
+			role:        constants.UserStudentRole,
+			dormitoryId: "02",
+			wantReason:  constants.DenialReasonOtherDormitory,
+		},
+		{
+			name:        "dormitory scope",
+			role:        constants.UserStudentRole,
+			dormitoryId: "02",
+			setup: func(repo *fakeRepository, user *dbtypes.User) {
+				repo.scopes[user.UserId] = []string{"02"}
+			},
+			wantAllowed: true,
+		},
+		{
+			name:        "suspended",
+			role:        constants.UserStudentRole,
+			dormitoryId: "01",
+			setup: func(repo *fakeRepository, user *dbtypes.User) {
+				user.Status = constants.UserStatusSuspended
+				user.StatusExpiresAt = &future
+			},
+			wantReason: constants.DenialReasonAccountInactive,
+		},
+		{
+			name:        "suspension ended",
+			role:        constants.UserStudentRole,
+			dormitoryId: "01",
+			setup: func(repo *fakeRepository, user *dbtypes.User) {
+				user.Status = constants.UserStatusSuspended
+				user.StatusExpiresAt = &past
+			},
+			wantAllowed: true,
+		},
+		{
+			name:        "other dormitory before status",
+			role:        constants.UserStudentRole,
+			dormitoryId: "02",
+			setup: func(repo *fakeRepository, user *dbtypes.User) {
+				user.Status = constants.UserStatusBanned
+			},
+			wantReason: constants.DenialReasonOtherDormitory,
+		},
+		{
+			name:        "super admin in any dormitory",
+			role:        constants.UserSuperAdminRole,
+			dormitoryId: "02",
+			wantAllowed: true,
+		},
+		{
+			name:        "banned super admin",
+			role:        constants.UserSuperAdminRole,
+			dormitoryId: "01",
+			setup: func(repo *fakeRepository, user *dbtypes.User) {
+				user.Status = constants.UserStatusBanned
+			},
+			wantReason: constants.DenialReasonAccountInactive,
+		},
+		{
+			name:        "role grant in effect",
+			role:        constants.UserMaintenanceRole,
+			dormitoryId: "01",
+			setup: func(repo *fakeRepository, user *dbtypes.User) {
+				repo.roleGrants = append(repo.roleGrants, dbtypes.RoleGrant{
+					UserId:     user.UserId,
+					Role:       constants.UserStudentRole,
+					ValidFrom:  past,
+					ValidUntil: future,
+				})
+			},
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			repo.rolePermissions[constants.UserStudentRole] = map[string]bool{testPermission: true}
+			user := repo.addUser(t, "01", tt.role)
+			if tt.setup != nil {
+				tt.setup(repo, user)
+			}
+
+			s := newTestService(t, repo)
+
+			resp, err := s.CheckPermission(context.Background(), &rmodel.CheckPermissionRequest{
+				UserId:      user.UserId,
+				DormitoryId: tt.dormitoryId,
+				Permission:  testPermission,
+			})
+			if err != nil {
+				t.Fatalf("CheckPermission() error = %v", err)
+			}
+
+			if resp.Allowed != tt.wantAllowed {
+				t.Errorf("CheckPermission() allowed = %v, want %v", resp.Allowed, tt.wantAllowed)
+			}
+
+			if resp.DenialReason != tt.wantReason {
+				t.Errorf("CheckPermission() denial reason = %q, want %q", resp.DenialReason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCheckPermissionBadRequest(t *testing.T) {
+	s := newTestService(t, newFakeRepository())
+
+	tests := []struct {
+		name    string
+		request *rmodel.CheckPermissionRequest
+		want    error
+	}{
+		{name: "no request", want: ErrBadRequest},
+		{name: "no user", request: &rmodel.CheckPermissionRequest{DormitoryId: "01", Permission: testPermission}, want: ErrBadRequest},
+		{name: "no permission", request: &rmodel.CheckPermissionRequest{UserId: uuid.NewString(), DormitoryId: "01"}, want: ErrBadRequest},
+		{name: "malformed user", request: &rmodel.CheckPermissionRequest{UserId: "42", DormitoryId: "01", Permission: testPermission}, want: ErrBadRequest},
+		{name: "unknown user", request: &rmodel.CheckPermissionRequest{UserId: uuid.NewString(), DormitoryId: "01", Permission: testPermission}, want: ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		if _, err := s.CheckPermission(context.Background(), tt.request); !errors.Is(err, tt.want) {
+			t.Errorf("CheckPermission() %s error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestBatchCheckPermission(t *testing.T) {
+	repo := newFakeRepository()
+	repo.rolePermissions[constants.UserStudentRole] = map[string]bool{testPermission: true}
+	student := repo.addUser(t, "01", constants.UserStudentRole)
+	s := newTestService(t, repo)
+
+	resp, err := s.BatchCheckPermission(context.Background(), &rmodel.BatchCheckPermissionRequest{
+		Checks: []rmodel.CheckPermissionRequest{
+			{UserId: student.UserId, DormitoryId: "01", Permission: testPermission},
+			{UserId: uuid.NewString(), DormitoryId: "01", Permission: testPermission},
+			{UserId: "42", DormitoryId: "01", Permission: testPermission},
+			{UserId: student.UserId, DormitoryId: "02", Permission: testPermission},
+		},
+	})
+	if err != nil {
+		t.Fatalf("BatchCheckPermission() error = %v", err)
+	}
+
+	want := []string{
+		"",
+		constants.DenialReasonUserNotFound,
+		constants.DenialReasonUserNotFound,
+		constants.DenialReasonOtherDormitory,
+	}
+
+	if len(resp.Results) != len(want) {
+		t.Fatalf("BatchCheckPermission() returned %d results, want %d", len(resp.Results), len(want))
+	}
+
+	for i, result := range resp.Results {
+		if result.DenialReason != want[i] {
+			t.Errorf("result %d denial reason = %q, want %q", i, result.DenialReason, want[i])
+		}
+
+		if result.Allowed != (want[i] == "") {
+			t.Errorf("result %d allowed = %v, want %v", i, result.Allowed, want[i] == "")
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+// Principal is the authenticated caller behind an access token. Role,
+// dormitory and dormitory scopes are read from the database, so changes apply
+// without waiting for the token to be refreshed.
+type Principal struct {
+	UserId      string
+	DormitoryId string
+	// Dormitories are the dormitories the user may act in besides their own.
+	Dormitories []string
+	Role        string
+	SessionId   string
+	ClientId    string
+	TokenId     string
+}
+
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if p.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InDormitory reports whether the caller may act in the dormitory: their own,
+// one of their scopes, or any of them for a super admin.
+func (p *Principal) InDormitory(dormitoryId string) bool {
+	if p.Role == constants.UserSuperAdminRole || p.DormitoryId == dormitoryId {
+		return true
+	}
+
+	return slices.Contains(p.Dormitories, dormitoryId)
+}
+
+func (s *AuthService) Authenticate(
+	ctx context.Context,
+	accessToken string,
+) (*Principal, error) {
+	claims, err := s.authenticate(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repository.GetUserById(ctx, &dbtypes.GetUserInfoByIdRequest{
+		Id: claims.UserId,
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: user not found", ErrUnauthorized)
+		}
+
+		return nil, fmt.Errorf("%w: error getting user while authenticating: %v", s.handleDBError(err), err)
+	}
+
+	if err := checkUserActive(user.Status, user.StatusExpiresAt); err != nil {
+		return nil, err
+	}
+
+	dormitories, err := s.dormitoryScopes(ctx, user.UserId, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Principal{
+		UserId:      user.UserId,
+		DormitoryId: user.DormitoryId,
+		Dormitories: dormitories,
+		Role:        user.Role,
+		SessionId:   claims.SessionId,
+		ClientId:    claims.ClientId,
+		TokenId:     claims.ID,
+	}, nil
+}
@@ -0,0 +1,333 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/config"
+	"github.com/dormitory-life/auth/internal/constants"
+	"github.com/dormitory-life/auth/internal/database"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/dormitory-life/auth/internal/jwtkeys"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "correct horse battery staple"
+
+// fakeRepository keeps users, refresh tokens and permissions in memory. The
+// embedded interface is nil, so a test calling anything else panics.
+type fakeRepository struct {
+	database.Repository
+
+	users      map[string]*dbtypes.User
+	roleGrants []dbtypes.RoleGrant
+	scopes     map[string][]string
+
+	createRoleGrantErr error
+
+	tokens          map[string]*dbtypes.RefreshToken
+	revokedFamilies map[string]bool
+
+	rolePermissions map[string]map[string]bool
+	grants          map[string]map[string]bool
+
+	// beforeRotate runs at the start of RotateRefreshToken, to let a test
+	// change the store between the read and the write of a rotation.
+	beforeRotate func()
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		users:           make(map[string]*dbtypes.User),
+		scopes:          make(map[string][]string),
+		tokens:          make(map[string]*dbtypes.RefreshToken),
+		revokedFamilies: make(map[string]bool),
+		rolePermissions: make(map[string]map[string]bool),
+		grants:          make(map[string]map[string]bool),
+	}
+}
+
+func (r *fakeRepository) addUser(t *testing.T, dormitoryId string, role string) *dbtypes.User {
+	t.Helper()
+
+	password, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() error = %v", err)
+	}
+
+	user := &dbtypes.User{
+		UserId:      uuid.NewString(),
+		Email:       uuid.NewString() + "@example.com",
+		Password:    string(password),
+		DormitoryId: dormitoryId,
+		Role:        role,
+		CreatedAt:   time.Now().UTC(),
+		Status:      constants.UserStatusActive,
+	}
+
+	r.users[user.UserId] = user
+
+	return user
+}
+
+// effectiveRole mirrors the query of the database: the role of the latest
+// grant in effect, the role stored on the user otherwise.
+func (r *fakeRepository) effectiveRole(user *dbtypes.User, now time.Time) string {
+	var latest *dbtypes.RoleGrant
+
+	for i := range r.roleGrants {
+		grant := &r.roleGrants[i]
+		if grant.UserId != user.UserId || grant.ValidFrom.After(now) || !grant.ValidUntil.After(now) {
+			continue
+		}
+
+		if latest == nil || grant.ValidFrom.After(latest.ValidFrom) {
+			latest = grant
+		}
+	}
+
+	if latest != nil {
+		return latest.Role
+	}
+
+	return user.Role
+}
+
+func (r *fakeRepository) GetUserByEmail(
+	ctx context.Context,
+	request *dbtypes.GetUserByEmailRequest,
+) (*dbtypes.GetUserResponse, error) {
+	for _, user := range r.users {
+		if user.Email != request.Email {
+			continue
+		}
+
+		return &dbtypes.GetUserResponse{
+			UserId:          user.UserId,
+			Email:           user.Email,
+			Password:        user.Password,
+			DormitoryId:     user.DormitoryId,
+			Role:            r.effectiveRole(user, time.Now().UTC()),
+			CreatedAt:       user.CreatedAt,
+			EmailVerifiedAt: user.EmailVerifiedAt,
+			Status:          user.Status,
+			StatusReason:    user.StatusReason,
+			StatusExpiresAt: user.StatusExpiresAt,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
+}
+
+func (r *fakeRepository) GetUserById(
+	ctx context.Context,
+	request *dbtypes.GetUserInfoByIdRequest,
+) (*dbtypes.GetUserInfoByIdResponse, error) {
+	user, ok := r.users[request.Id]
+	if !ok {
+		return nil, fmt.Errorf("%w: user not found", dberrors.ErrNotFound)
+	}
+
+	return &dbtypes.GetUserInfoByIdResponse{
+		UserId:          user.UserId,
+		Email:           user.Email,
+		DormitoryId:     user.DormitoryId,
+		Role:            r.effectiveRole(user, time.Now().UTC()),
+		CreatedAt:       user.CreatedAt,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		Status:          user.Status,
+		StatusReason:    user.StatusReason,
+		StatusExpiresAt: user.StatusExpiresAt,
+	}, nil
+}
+
+func (r *fakeRepository) GetUsersByIds(
+	ctx context.Context,
+	request *dbtypes.GetUsersByIdsRequest,
+) (*dbtypes.GetUsersByIdsResponse, error) {
+	resp := &dbtypes.GetUsersByIdsResponse{}
+	for _, id := range request.Ids {
+		if user, ok := r.users[id]; ok {
+			found := *user
+			found.Role = r.effectiveRole(user, time.Now().UTC())
+			resp.Users = append(resp.Users, found)
+		}
+	}
+
+	return resp, nil
+}
+
+func (r *fakeRepository) GetUserDormitoryScopes(
+	ctx context.Context,
+	request *dbtypes.GetUserDormitoryScopesRequest,
+) (*dbtypes.GetUserDormitoryScopesResponse, error) {
+	resp := &dbtypes.GetUserDormitoryScopesResponse{}
+	for _, dormitoryId := range r.scopes[request.UserId] {
+		resp.Scopes = append(resp.Scopes, dbtypes.DormitoryScope{
+			UserId:      request.UserId,
+			DormitoryId: dormitoryId,
+		})
+	}
+
+	return resp, nil
+}
+
+func (r *fakeRepository) CreateRoleGrant(
+	ctx context.Context,
+	request *dbtypes.CreateRoleGrantRequest,
+) (*dbtypes.CreateRoleGrantResponse, error) {
+	if r.createRoleGrantErr != nil {
+		return nil, r.createRoleGrantErr
+	}
+
+	grant := dbtypes.RoleGrant{
+		Id:          uuid.NewString(),
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+		Role:        request.Role,
+		ValidFrom:   request.ValidFrom,
+		ValidUntil:  request.ValidUntil,
+		GrantedBy:   &request.GrantedBy,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	r.roleGrants = append(r.roleGrants, grant)
+
+	return &dbtypes.CreateRoleGrantResponse{Grant: &grant}, nil
+}
+
+func (r *fakeRepository) GetPermissionSets(
+	ctx context.Context,
+	request *dbtypes.GetPermissionSetsRequest,
+) (*dbtypes.GetPermissionSetsResponse, error) {
+	resp := &dbtypes.GetPermissionSetsResponse{
+		RolePermissions: r.rolePermissions,
+		Grants:          r.grants,
+		Scopes:          make(map[string]map[string]bool),
+	}
+
+	for userId, dormitories := range r.scopes {
+		resp.Scopes[userId] = make(map[string]bool, len(dormitories))
+		for _, dormitoryId := range dormitories {
+			resp.Scopes[userId][dormitoryId] = true
+		}
+	}
+
+	return resp, nil
+}
+
+func (r *fakeRepository) CreateRefreshToken(
+	ctx context.Context,
+	request *dbtypes.CreateRefreshTokenRequest,
+) (*dbtypes.CreateRefreshTokenResponse, error) {
+	token := &dbtypes.RefreshToken{
+		Id:        uuid.NewString(),
+		UserId:    request.UserId,
+		FamilyId:  request.FamilyId,
+		ClientId:  request.ClientId,
+		TokenHash: request.TokenHash,
+		ExpiresAt: request.ExpiresAt,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	r.tokens[token.TokenHash] = token
+
+	return &dbtypes.CreateRefreshTokenResponse{Id: token.Id}, nil
+}
+
+// GetRefreshTokenByHash returns a copy, as a read from the database would.
+func (r *fakeRepository) GetRefreshTokenByHash(
+	ctx context.Context,
+	request *dbtypes.GetRefreshTokenByHashRequest,
+) (*dbtypes.GetRefreshTokenResponse, error) {
+	token, ok := r.tokens[request.TokenHash]
+	if !ok {
+		return nil, fmt.Errorf("%w: refresh token not found", dberrors.ErrNotFound)
+	}
+
+	stored := *token
+
+	return &dbtypes.GetRefreshTokenResponse{Token: &stored}, nil
+}
+
+func (r *fakeRepository) RotateRefreshToken(
+	ctx context.Context,
+	request *dbtypes.RotateRefreshTokenRequest,
+) error {
+	if r.beforeRotate != nil {
+		r.beforeRotate()
+	}
+
+	used := r.tokenById(request.UsedTokenId)
+	if used == nil || used.UsedAt != nil || used.RevokedAt != nil {
+		return fmt.Errorf("%w: refresh token already used", dberrors.ErrConflict)
+	}
+
+	now := time.Now().UTC()
+	used.UsedAt = &now
+
+	_, err := r.CreateRefreshToken(ctx, request.NewToken)
+
+	return err
+}
+
+func (r *fakeRepository) RevokeRefreshTokenFamily(
+	ctx context.Context,
+	request *dbtypes.RevokeRefreshTokenFamilyRequest,
+) error {
+	now := time.Now().UTC()
+
+	r.revokedFamilies[request.FamilyId] = true
+
+	for _, token := range r.tokens {
+		if token.FamilyId == request.FamilyId && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+
+	return nil
+}
+
+func (r *fakeRepository) IsSessionActive(
+	ctx context.Context,
+	request *dbtypes.IsSessionActiveRequest,
+) (*dbtypes.IsSessionActiveResponse, error) {
+	return &dbtypes.IsSessionActiveResponse{
+		Active: !r.revokedFamilies[request.FamilyId],
+	}, nil
+}
+
+func (r *fakeRepository) tokenById(id string) *dbtypes.RefreshToken {
+	for _, token := range r.tokens {
+		if token.Id == id {
+			return token
+		}
+	}
+
+	return nil
+}
+
+func newTestService(t *testing.T, repository database.Repository) *AuthService {
+	t.Helper()
+
+	keyring, err := jwtkeys.LoadKeyring(config.JWTConfig{
+		Secret:    "test-secret-with-enough-bytes-for-hs256",
+		Algorithm: "HS256",
+	})
+	if err != nil {
+		t.Fatalf("LoadKeyring() error = %v", err)
+	}
+
+	return New(AuthServiceConfig{
+		Repository:      repository,
+		Keyring:         keyring,
+		Issuer:          "dormitory-life-auth",
+		Audience:        []string{"dormitory-life"},
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 24 * time.Hour,
+	}).(*AuthService)
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+func (s *AuthService) ChangeUserRole(
+	ctx context.Context,
+	request *rmodel.ChangeUserRoleRequest,
+) (*rmodel.AdminUser, error) {
+	if request == nil || request.DormitoryId == "" || request.UserId == "" || request.ActorId == "" {
+		return nil, ErrBadRequest
+	}
+
+	if !isKnownRole(request.Role) {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrBadRequest, request.Role)
+	}
+
+	user, err := s.updateUserRole(ctx, &dbtypes.UpdateUserRoleRequest{
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+		Role:        request.Role,
+		ActorId:     request.ActorId,
+		ActorSource: dbtypes.RoleChangeSourceAPI,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return new(rmodel.AdminUser).From(user), nil
+}
+
+func (s *AuthService) ListRoleChanges(
+	ctx context.Context,
+	request *rmodel.ListRoleChangesRequest,
+) (*rmodel.ListRoleChangesResponse, error) {
+	if request == nil || request.DormitoryId == "" {
+		return nil, ErrBadRequest
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultUsersPageSize
+	}
+
+	if limit > maxUsersPageSize {
+		limit = maxUsersPageSize
+	}
+
+	resp, err := s.repository.ListRoleChanges(ctx, &dbtypes.ListRoleChangesRequest{
+		DormitoryId: request.DormitoryId,
+		UserId:      request.UserId,
+		Limit:       limit,
+		Offset:      request.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error listing role changes: %v", s.handleDBError(err), err)
+	}
+
+	result := &rmodel.ListRoleChangesResponse{
+		Changes: make([]*rmodel.RoleChange, 0, len(resp.Changes)),
+		Limit:   limit,
+		Offset:  request.Offset,
+	}
+
+	for i := range resp.Changes {
+		result.Changes = append(result.Changes, new(rmodel.RoleChange).From(&resp.Changes[i]))
+	}
+
+	return result, nil
+}
+
+func (s *AuthService) updateUserRole(
+	ctx context.Context,
+	request *dbtypes.UpdateUserRoleRequest,
+) (*dbtypes.User, error) {
+	if request.Role == constants.UserSuperAdminRole {
+		return nil, fmt.Errorf("%w: the super admin role can only be given with authctl", ErrForbidden)
+	}
+
+	resp, err := s.repository.UpdateUserRole(ctx, request)
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: user not found", ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error updating user role: %v", s.handleDBError(err), err)
+	}
+
+	return resp.User, nil
+}
@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+// CreateRoleGrant gives a user of the dormitory a role for a period. While
+// the grant is in effect it replaces the role of the user; when it ends the
+// grant sweeper revokes the sessions of the user.
+func (s *AuthService) CreateRoleGrant(
+	ctx context.Context,
+	request *rmodel.CreateRoleGrantRequest,
+) (*rmodel.RoleGrant, error) {
+	if request == nil || request.DormitoryId == "" || request.UserId == "" || request.ActorId == "" {
+		return nil, ErrBadRequest
+	}
+
+	if !isKnownRole(request.Role) {
+		return nil, fmt.Errorf("%w: unknown role %q", ErrBadRequest, request.Role)
+	}
+
+	if request.Role == constants.UserSuperAdminRole {
+		return nil, fmt.Errorf("%w: the super admin role can only be given with authctl", ErrForbidden)
+	}
+
+	now := time.Now().UTC()
+
+	validFrom := now
+	if request.ValidFrom != nil {
+		validFrom = request.ValidFrom.UTC()
+	}
+
+	validUntil := request.ValidUntil.UTC()
+
+	if err := validateGrantPeriod(&validFrom, &validUntil, now); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.repository.CreateRoleGrant(ctx, &dbtypes.CreateRoleGrantRequest{
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+		Role:        request.Role,
+		ValidFrom:   validFrom,
+		ValidUntil:  validUntil,
+		GrantedBy:   request.ActorId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating role grant: %v", s.handleDBError(err), err)
+	}
+
+	return new(rmodel.RoleGrant).From(resp.Grant), nil
+}
+
+func (s *AuthService) ListRoleGrants(
+	ctx context.Context,
+	request *rmodel.ListRoleGrantsRequest,
+) (*rmodel.ListRoleGrantsResponse, error) {
+	if request == nil || request.DormitoryId == "" || request.UserId == "" {
+		return nil, ErrBadRequest
+	}
+
+	resp, err := s.repository.ListRoleGrants(ctx, &dbtypes.ListRoleGrantsRequest{
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error listing role grants: %v", s.handleDBError(err), err)
+	}
+
+	result := &rmodel.ListRoleGrantsResponse{
+		Grants: make([]*rmodel.RoleGrant, 0, len(resp.Grants)),
+	}
+
+	for i := range resp.Grants {
+		result.Grants = append(result.Grants, new(rmodel.RoleGrant).From(&resp.Grants[i]))
+	}
+
+	return result, nil
+}
+
+// EndRoleGrant ends a role grant now, or cancels it if it has not started.
+func (s *AuthService) EndRoleGrant(
+	ctx context.Context,
+	request *rmodel.EndRoleGrantRequest,
+) error {
+	if request == nil || request.DormitoryId == "" || request.UserId == "" || request.GrantId == "" {
+		return ErrBadRequest
+	}
+
+	err := s.repository.EndRoleGrant(ctx, &dbtypes.EndRoleGrantRequest{
+		Id:          request.GrantId,
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+		At:          time.Now().UTC(),
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return fmt.Errorf("%w: role grant not found", ErrNotFound)
+		}
+
+		return fmt.Errorf("%w: error ending role grant: %v", s.handleDBError(err), err)
+	}
+
+	return nil
+}
+
+// validateGrantPeriod checks the optional bounds of a grant.
+func validateGrantPeriod(validFrom *time.Time, validUntil *time.Time, now time.Time) error {
+	if validUntil == nil {
+		return nil
+	}
+
+	if !validUntil.After(now) {
+		return fmt.Errorf("%w: valid_until must be in the future", ErrBadRequest)
+	}
+
+	if validFrom != nil && !validUntil.After(*validFrom) {
+		return fmt.Errorf("%w: valid_until must be after valid_from", ErrBadRequest)
+	}
+
+	return nil
+}
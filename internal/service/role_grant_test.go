@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+func TestEffectiveRole(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name   string
+		grants []dbtypes.RoleGrant
+		want   string
+	}{
+		{
+			name: "no grant",
+			want: constants.UserStudentRole,
+		},
+		{
+			name: "grant in effect",
+			grants: []dbtypes.RoleGrant{
+				{Role: constants.UserFloorWardenRole, ValidFrom: now.Add(-time.Hour), ValidUntil: now.Add(time.Hour)},
+			},
+			want: constants.UserFloorWardenRole,
+		},
+		{
+			name: "grant not started",
+			grants: []dbtypes.RoleGrant{
+				{Role: constants.UserFloorWardenRole, ValidFrom: now.Add(time.Hour), ValidUntil: now.Add(2 * time.Hour)},
+			},
+			want: constants.UserStudentRole,
+		},
+		{
+			name: "grant ended",
+			grants: []dbtypes.RoleGrant{
+				{Role: constants.UserFloorWardenRole, ValidFrom: now.Add(-2 * time.Hour), ValidUntil: now.Add(-time.Hour)},
+			},
+			want: constants.UserStudentRole,
+		},
+		{
+			name: "latest grant wins",
+			grants: []dbtypes.RoleGrant{
+				{Role: constants.UserFloorWardenRole, ValidFrom: now.Add(-2 * time.Hour), ValidUntil: now.Add(time.Hour)},
+				{Role: constants.UserAdminRole, ValidFrom: now.Add(-time.Hour), ValidUntil: now.Add(time.Hour)},
+			},
+			want: constants.UserAdminRole,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newFakeRepository()
+			user := repo.addUser(t, "01", constants.UserStudentRole)
+			s := newTestService(t, repo)
+
+			session := login(t, s, user.Email)
+
+			for _, grant := range tt.grants {
+				grant.UserId = user.UserId
+				repo.roleGrants = append(repo.roleGrants, grant)
+			}
+
+			principal, err := s.Authenticate(ctx, session.AccessToken)
+			if err != nil {
+				t.Fatalf("Authenticate() error = %v", err)
+			}
+
+			if principal.Role != tt.want {
+				t.Errorf("Authenticate() role = %q, want %q", principal.Role, tt.want)
+			}
+
+			resp, err := s.RefreshTokens(ctx, &rmodel.RefreshTokensRequest{RefreshToken: session.RefreshToken})
+			if err != nil {
+				t.Fatalf("RefreshTokens() error = %v", err)
+			}
+
+			claims, err := s.parseJWTToken(resp.AccessToken, "access")
+			if err != nil {
+				t.Fatalf("parseJWTToken() error = %v", err)
+			}
+
+			if claims.Role != tt.want {
+				t.Errorf("refreshed token role = %q, want %q", claims.Role, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateRoleGrant(t *testing.T) {
+	validUntil := time.Now().UTC().Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		role    string
+		repoErr error
+		want    error
+	}{
+		{
+			name: "granted",
+			role: constants.UserFloorWardenRole,
+		},
+		{
+			name: "unknown role",
+			role: "janitor",
+			want: ErrBadRequest,
+		},
+		{
+			name: "super admin role",
+			role: constants.UserSuperAdminRole,
+			want: ErrForbidden,
+		},
+		{
+			name:    "super admin user",
+			role:    constants.UserFloorWardenRole,
+			repoErr: fmt.Errorf("%w: a super admin cannot be given a role grant", dberrors.ErrForbidden),
+			want:    ErrForbidden,
+		},
+		{
+			name:    "user of another dormitory",
+			role:    constants.UserFloorWardenRole,
+			repoErr: fmt.Errorf("%w: user not found", dberrors.ErrNotFound),
+			want:    ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			repo.createRoleGrantErr = tt.repoErr
+			user := repo.addUser(t, "01", constants.UserStudentRole)
+			s := newTestService(t, repo)
+
+			grant, err := s.CreateRoleGrant(context.Background(), &rmodel.CreateRoleGrantRequest{
+				ActorId:     user.UserId,
+				DormitoryId: user.DormitoryId,
+				UserId:      user.UserId,
+				Role:        tt.role,
+				ValidUntil:  validUntil,
+			})
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Errorf("CreateRoleGrant() error = %v, want %v", err, tt.want)
+				}
+
+				if len(repo.roleGrants) != 0 {
+					t.Errorf("CreateRoleGrant() stored %d grants, want none", len(repo.roleGrants))
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CreateRoleGrant() error = %v", err)
+			}
+
+			if grant.Role != tt.role {
+				t.Errorf("CreateRoleGrant() role = %q, want %q", grant.Role, tt.role)
+			}
+		})
+	}
+}
+
+func TestValidateGrantPeriod(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	tests := []struct {
+		name       string
+		validFrom  *time.Time
+		validUntil *time.Time
+		want       error
+	}{
+		{name: "no end", validFrom: at(-time.Hour)},
+		{name: "ends in the future", validUntil: at(time.Hour)},
+		{name: "starts later", validFrom: at(time.Hour), validUntil: at(2 * time.Hour)},
+		{name: "ends now", validUntil: at(0), want: ErrBadRequest},
+		{name: "ended", validUntil: at(-time.Hour), want: ErrBadRequest},
+		{name: "ends before start", validFrom: at(2 * time.Hour), validUntil: at(time.Hour), want: ErrBadRequest},
+		{name: "ends at start", validFrom: at(time.Hour), validUntil: at(time.Hour), want: ErrBadRequest},
+	}
+
+	for _, tt := range tests {
+		if err := validateGrantPeriod(tt.validFrom, tt.validUntil, now); !errors.Is(err, tt.want) {
+			t.Errorf("validateGrantPeriod() %s error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+// dormitoryScopes returns the dormitories the user may act in besides their
+// own. Super admins act in every dormitory, so their scopes are not read.
+func (s *AuthService) dormitoryScopes(
+	ctx context.Context,
+	userId string,
+	role string,
+) ([]string, error) {
+	if role == constants.UserSuperAdminRole {
+		return nil, nil
+	}
+
+	resp, err := s.repository.GetUserDormitoryScopes(ctx, &dbtypes.GetUserDormitoryScopesRequest{
+		UserId: userId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error getting dormitory scopes: %v", s.handleDBError(err), err)
+	}
+
+	dormitories := make([]string, 0, len(resp.Scopes))
+	for _, scope := range resp.Scopes {
+		dormitories = append(dormitories, scope.DormitoryId)
+	}
+
+	return dormitories, nil
+}
+
+func (s *AuthService) ListDormitoryScopes(
+	ctx context.Context,
+	request *rmodel.DormitoryScopesRequest,
+) (*rmodel.DormitoryScopesResponse, error) {
+	if request == nil || request.UserId == "" {
+		return nil, ErrBadRequest
+	}
+
+	resp, err := s.repository.GetUserDormitoryScopes(ctx, &dbtypes.GetUserDormitoryScopesRequest{
+		UserId: request.UserId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error getting dormitory scopes: %v", s.handleDBError(err), err)
+	}
+
+	result := &rmodel.DormitoryScopesResponse{
+		UserId: request.UserId,
+		Scopes: make([]*rmodel.DormitoryScope, 0, len(resp.Scopes)),
+	}
+
+	for i := range resp.Scopes {
+		result.Scopes = append(result.Scopes, new(rmodel.DormitoryScope).From(&resp.Scopes[i]))
+	}
+
+	return result, nil
+}
+
+func (s *AuthService) AddDormitoryScope(
+	ctx context.Context,
+	request *rmodel.AddDormitoryScopeRequest,
+) (*rmodel.DormitoryScopesResponse, error) {
+	if request == nil || request.UserId == "" || request.DormitoryId == "" {
+		return nil, ErrBadRequest
+	}
+
+	err := s.repository.AddDormitoryScope(ctx, &dbtypes.AddDormitoryScopeRequest{
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+		GrantedBy:   request.ActorId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error adding dormitory scope: %v", s.handleDBError(err), err)
+	}
+
+	return s.ListDormitoryScopes(ctx, &rmodel.DormitoryScopesRequest{
+		UserId: request.UserId,
+	})
+}
+
+func (s *AuthService) RemoveDormitoryScope(
+	ctx context.Context,
+	request *rmodel.RemoveDormitoryScopeRequest,
+) error {
+	if request == nil || request.UserId == "" || request.DormitoryId == "" {
+		return ErrBadRequest
+	}
+
+	err := s.repository.RemoveDormitoryScope(ctx, &dbtypes.RemoveDormitoryScopeRequest{
+		UserId:      request.UserId,
+		DormitoryId: request.DormitoryId,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: error removing dormitory scope: %v", s.handleDBError(err), err)
+	}
+
+	return nil
+}
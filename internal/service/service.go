@@ -4,36 +4,148 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/dormitory-life/auth/internal/config"
+	"github.com/dormitory-life/auth/internal/constants"
 	"github.com/dormitory-life/auth/internal/database"
 	dberrors "github.com/dormitory-life/auth/internal/database/errors"
 	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/dormitory-life/auth/internal/jwtkeys"
+	"github.com/dormitory-life/auth/internal/notifier"
 	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+	"github.com/dormitory-life/auth/internal/webhook"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthServiceConfig struct {
-	Repository database.Repository
-	JWTSecret  string
+	Repository      database.Repository
+	Logger          *slog.Logger
+	Keyring         *jwtkeys.Keyring
+	Issuer          string
+	Audience        []string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	Clients         map[string]config.TokenLifetimeConfig
+
+	Notifier                 notifier.Notifier
+	PasswordResetTTL         time.Duration
+	EmailVerificationTTL     time.Duration
+	RequireEmailVerification bool
+
+	// WebhookTargets limits the URLs webhooks can be created for. Nil allows
+	// public addresses only.
+	WebhookTargets *webhook.TargetPolicy
 }
 type AuthService struct {
-	repository database.Repository
-	jwtSecret  string
+	repository      database.Repository
+	logger          *slog.Logger
+	keyring         *jwtkeys.Keyring
+	issuer          string
+	audience        []string
+	defaultLifetime tokenLifetime
+	clientLifetimes map[string]tokenLifetime
+
+	notifier                 notifier.Notifier
+	passwordResetTTL         time.Duration
+	emailVerificationTTL     time.Duration
+	requireEmailVerification bool
+
+	webhookTargets *webhook.TargetPolicy
 }
 
 type AuthServiceClient interface {
 	Register(ctx context.Context, request *rmodel.RegisterRequest) (*rmodel.RegisterResponse, error)
 	Login(ctx context.Context, request *rmodel.LoginRequest) (*rmodel.LoginResponse, error)
 	RefreshTokens(ctx context.Context, request *rmodel.RefreshTokensRequest) (*rmodel.RefreshTokensResponse, error)
+	Logout(ctx context.Context, request *rmodel.LogoutRequest) error
+	LogoutAll(ctx context.Context, request *rmodel.LogoutRequest) error
+
+	Authenticate(ctx context.Context, accessToken string) (*Principal, error)
+
+	GetJWKS(ctx context.Context) (*rmodel.JWKSResponse, error)
+	IntrospectToken(ctx context.Context, request *rmodel.IntrospectRequest) (*rmodel.IntrospectResponse, error)
+
+	ForgotPassword(ctx context.Context, request *rmodel.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, request *rmodel.ResetPasswordRequest) error
+	ChangePassword(ctx context.Context, request *rmodel.ChangePasswordRequest) error
+
+	VerifyEmail(ctx context.Context, request *rmodel.VerifyEmailRequest) error
+	ResendVerificationEmail(ctx context.Context, request *rmodel.ResendVerificationEmailRequest) error
 
 	GetUserInfoById(ctx context.Context, request *rmodel.GetUserByIdRequest) (*rmodel.GetUserByIdResponse, error)
+
+	ListUsers(ctx context.Context, request *rmodel.ListUsersRequest) (*rmodel.ListUsersResponse, error)
+	AdminGetUser(ctx context.Context, request *rmodel.AdminGetUserRequest) (*rmodel.AdminUser, error)
+	AdminUpdateUser(ctx context.Context, request *rmodel.AdminUpdateUserRequest) (*rmodel.AdminUser, error)
+	ChangeUserRole(ctx context.Context, request *rmodel.ChangeUserRoleRequest) (*rmodel.AdminUser, error)
+	ListRoleChanges(ctx context.Context, request *rmodel.ListRoleChangesRequest) (*rmodel.ListRoleChangesResponse, error)
+	CreateRoleGrant(ctx context.Context, request *rmodel.CreateRoleGrantRequest) (*rmodel.RoleGrant, error)
+	ListRoleGrants(ctx context.Context, request *rmodel.ListRoleGrantsRequest) (*rmodel.ListRoleGrantsResponse, error)
+	EndRoleGrant(ctx context.Context, request *rmodel.EndRoleGrantRequest) error
+
+	CreateWebhook(ctx context.Context, request *rmodel.CreateWebhookRequest) (*rmodel.Webhook, error)
+	ListWebhooks(ctx context.Context, request *rmodel.ListWebhooksRequest) (*rmodel.ListWebhooksResponse, error)
+	UpdateWebhook(ctx context.Context, request *rmodel.UpdateWebhookRequest) (*rmodel.Webhook, error)
+	RotateWebhookSecret(ctx context.Context, request *rmodel.RotateWebhookSecretRequest) (*rmodel.Webhook, error)
+	DeleteWebhook(ctx context.Context, request *rmodel.DeleteWebhookRequest) error
+	ListWebhookDeliveries(ctx context.Context, request *rmodel.ListWebhookDeliveriesRequest) (*rmodel.ListWebhookDeliveriesResponse, error)
+	ListWebhookDeliveryAttempts(ctx context.Context, request *rmodel.ListWebhookDeliveryAttemptsRequest) (*rmodel.ListWebhookDeliveryAttemptsResponse, error)
+	RetryWebhookDelivery(ctx context.Context, request *rmodel.RetryWebhookDeliveryRequest) error
+
+	CheckPermission(ctx context.Context, request *rmodel.CheckPermissionRequest) (*rmodel.CheckPermissionResponse, error)
+	BatchCheckPermission(ctx context.Context, request *rmodel.BatchCheckPermissionRequest) (*rmodel.BatchCheckPermissionResponse, error)
+	WatchUserChanges(ctx context.Context, request *rmodel.WatchUserChangesRequest, send func(change *rmodel.UserChange) error) error
+	ListPermissions(ctx context.Context) (*rmodel.ListPermissionsResponse, error)
+	GetUserPermissions(ctx context.Context, request *rmodel.UserPermissionsRequest) (*rmodel.UserPermissionsResponse, error)
+	GrantPermission(ctx context.Context, request *rmodel.GrantPermissionRequest) (*rmodel.UserPermissionsResponse, error)
+	RevokePermission(ctx context.Context, request *rmodel.RevokePermissionRequest) error
+
+	ListDormitoryScopes(ctx context.Context, request *rmodel.DormitoryScopesRequest) (*rmodel.DormitoryScopesResponse, error)
+	AddDormitoryScope(ctx context.Context, request *rmodel.AddDormitoryScopeRequest) (*rmodel.DormitoryScopesResponse, error)
+	RemoveDormitoryScope(ctx context.Context, request *rmodel.RemoveDormitoryScopeRequest) error
 }
 
 func New(cfg AuthServiceConfig) AuthServiceClient {
+	defaultLifetime, clientLifetimes := newTokenLifetimes(cfg.AccessTokenTTL, cfg.RefreshTokenTTL, cfg.Clients)
+
+	passwordResetTTL := cfg.PasswordResetTTL
+	if passwordResetTTL <= 0 {
+		passwordResetTTL = constants.PasswordResetTokenTTL
+	}
+
+	emailVerificationTTL := cfg.EmailVerificationTTL
+	if emailVerificationTTL <= 0 {
+		emailVerificationTTL = constants.EmailVerificationTokenTTL
+	}
+
+	webhookTargets := cfg.WebhookTargets
+	if webhookTargets == nil {
+		webhookTargets = &webhook.TargetPolicy{}
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	return &AuthService{
-		repository: cfg.Repository,
-		jwtSecret:  cfg.JWTSecret,
+		repository:      cfg.Repository,
+		logger:          logger,
+		keyring:         cfg.Keyring,
+		issuer:          cfg.Issuer,
+		audience:        cfg.Audience,
+		defaultLifetime: defaultLifetime,
+		clientLifetimes: clientLifetimes,
+
+		notifier:                 cfg.Notifier,
+		passwordResetTTL:         passwordResetTTL,
+		emailVerificationTTL:     emailVerificationTTL,
+		requireEmailVerification: cfg.RequireEmailVerification,
+
+		webhookTargets: webhookTargets,
 	}
 }
 
@@ -45,6 +157,10 @@ func (s *AuthService) Register(
 		return nil, ErrBadRequest
 	}
 
+	if err := s.validateClientId(request.ClientId); err != nil {
+		return nil, err
+	}
+
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), bcrypt.DefaultCost)
 
 	resp, err := s.repository.Register(ctx, &dbtypes.RegisterRequest{
@@ -58,13 +174,33 @@ func (s *AuthService) Register(
 
 	result := new(rmodel.RegisterResponse).From(resp)
 
-	accessToken, refreshToken, err := s.generateJWTTokens(ctx, result.UserId, result.DormitoryId)
+	// the user is saved already, failing here would turn the retry of the
+	// client into a conflict; a lost email is sent again on request
+	if err := s.sendEmailVerification(ctx, result.UserId, request.Email); err != nil {
+		s.logger.Error("error sending email verification after register",
+			slog.String("error", err.Error()),
+			slog.String("user_id", result.UserId),
+		)
+	}
+
+	// unverified accounts cannot log in, so they do not get a session either
+	if s.requireEmailVerification {
+		result.EmailVerificationRequired = true
+		return result, nil
+	}
+
+	tokens, err := s.startSession(ctx, &tokenSubject{
+		userId:      result.UserId,
+		dormitoryId: result.DormitoryId,
+		role:        constants.UserStudentRole,
+		clientId:    request.ClientId,
+	}, false)
 	if err != nil {
 		return nil, fmt.Errorf("%w: error register user: %v", s.handleDBError(err), err)
 	}
 
-	result.AccessToken = accessToken
-	result.RefreshToken = refreshToken
+	result.AccessToken = tokens.accessToken
+	result.RefreshToken = tokens.refreshToken
 
 	return result, nil
 }
@@ -77,6 +213,10 @@ func (s *AuthService) Login(
 		return nil, ErrBadRequest
 	}
 
+	if err := s.validateClientId(request.ClientId); err != nil {
+		return nil, err
+	}
+
 	resp, err := s.repository.GetUserByEmail(ctx, &dbtypes.GetUserByEmailRequest{
 		Email: request.Email,
 	})
@@ -89,18 +229,37 @@ func (s *AuthService) Login(
 		return nil, fmt.Errorf("%w: incorrect password", ErrUnauthorized)
 	}
 
+	if err := checkUserActive(resp.Status, resp.StatusExpiresAt); err != nil {
+		return nil, err
+	}
+
+	if s.requireEmailVerification && resp.EmailVerifiedAt == nil {
+		return nil, fmt.Errorf("%w: email is not verified", ErrForbidden)
+	}
+
+	dormitories, err := s.dormitoryScopes(ctx, resp.UserId, resp.Role)
+	if err != nil {
+		return nil, err
+	}
+
 	result := &rmodel.LoginResponse{
 		UserId:      resp.UserId,
 		DormitoryId: resp.DormitoryId,
 	}
 
-	accessToken, refreshToken, err := s.generateJWTTokens(ctx, result.UserId, result.DormitoryId)
+	tokens, err := s.startSession(ctx, &tokenSubject{
+		userId:      result.UserId,
+		dormitoryId: result.DormitoryId,
+		dormitories: dormitories,
+		role:        resp.Role,
+		clientId:    request.ClientId,
+	}, true)
 	if err != nil {
 		return nil, fmt.Errorf("%w: error register user: %v", s.handleDBError(err), err)
 	}
 
-	result.AccessToken = accessToken
-	result.RefreshToken = refreshToken
+	result.AccessToken = tokens.accessToken
+	result.RefreshToken = tokens.refreshToken
 
 	return result, nil
 }
@@ -109,18 +268,84 @@ func (s *AuthService) RefreshTokens(
 	ctx context.Context,
 	request *rmodel.RefreshTokensRequest,
 ) (*rmodel.RefreshTokensResponse, error) {
-	if request == nil {
+	if request == nil || request.RefreshToken == "" {
 		return nil, ErrBadRequest
 	}
 
-	accessToken, refreshToken, err := s.generateJWTTokens(ctx, request.UserId, request.DormitoryId)
+	token, err := s.getStoredRefreshToken(ctx, request.RefreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.RevokedAt != nil {
+		return nil, fmt.Errorf("%w: refresh token revoked", ErrUnauthorized)
+	}
+
+	if token.UsedAt != nil {
+		if err := s.revokeFamily(ctx, token.FamilyId); err != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: refresh token reuse detected", ErrUnauthorized)
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return nil, fmt.Errorf("%w: refresh token expired", ErrUnauthorized)
+	}
+
+	user, err := s.repository.GetUserById(ctx, &dbtypes.GetUserInfoByIdRequest{
+		Id: token.UserId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error getting user while refreshing tokens: %v", s.handleDBError(err), err)
+	}
+
+	if err := checkUserActive(user.Status, user.StatusExpiresAt); err != nil {
+		return nil, err
+	}
+
+	dormitories, err := s.dormitoryScopes(ctx, user.UserId, user.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := s.generateJWTTokens(ctx, &tokenSubject{
+		userId:      user.UserId,
+		dormitoryId: user.DormitoryId,
+		dormitories: dormitories,
+		role:        user.Role,
+		sessionId:   token.FamilyId,
+		clientId:    token.ClientId,
+	})
 	if err != nil {
 		return nil, fmt.Errorf("%w: error refreshing tokens: %v", ErrInternal, err)
 	}
 
+	err = s.repository.RotateRefreshToken(ctx, &dbtypes.RotateRefreshTokenRequest{
+		UsedTokenId: token.Id,
+		NewToken: &dbtypes.CreateRefreshTokenRequest{
+			UserId:    user.UserId,
+			FamilyId:  token.FamilyId,
+			ClientId:  token.ClientId,
+			TokenHash: hashToken(tokens.refreshToken),
+			ExpiresAt: tokens.refreshExpiresAt,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrConflict) {
+			if err := s.revokeFamily(ctx, token.FamilyId); err != nil {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("%w: refresh token reuse detected", ErrUnauthorized)
+		}
+
+		return nil, fmt.Errorf("%w: error rotating refresh token: %v", s.handleDBError(err), err)
+	}
+
 	return &rmodel.RefreshTokensResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+		AccessToken:  tokens.accessToken,
+		RefreshToken: tokens.refreshToken,
 	}, nil
 }
 
@@ -134,6 +359,8 @@ func (s *AuthService) handleDBError(err error) error {
 		return ErrInternal
 	case errors.Is(err, dberrors.ErrConflict):
 		return ErrConflict
+	case errors.Is(err, dberrors.ErrForbidden):
+		return ErrForbidden
 	default:
 		return ErrInternal
 	}
@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+	"github.com/google/uuid"
+)
+
+func (s *AuthService) Logout(
+	ctx context.Context,
+	request *rmodel.LogoutRequest,
+) error {
+	if request == nil || request.RefreshToken == "" {
+		return ErrBadRequest
+	}
+
+	token, err := s.getStoredRefreshToken(ctx, request.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	if token.RevokedAt != nil {
+		return nil
+	}
+
+	return s.revokeFamily(ctx, token.FamilyId)
+}
+
+func (s *AuthService) LogoutAll(
+	ctx context.Context,
+	request *rmodel.LogoutRequest,
+) error {
+	if request == nil || request.RefreshToken == "" {
+		return ErrBadRequest
+	}
+
+	token, err := s.getStoredRefreshToken(ctx, request.RefreshToken)
+	if err != nil {
+		return err
+	}
+
+	if token.RevokedAt != nil {
+		return fmt.Errorf("%w: refresh token revoked", ErrUnauthorized)
+	}
+
+	err = s.repository.RevokeUserRefreshTokens(ctx, &dbtypes.RevokeUserRefreshTokensRequest{
+		UserId: token.UserId,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: error revoking user refresh tokens: %v", s.handleDBError(err), err)
+	}
+
+	return nil
+}
+
+// authenticate checks an access token the same way protected routes do: the
+// signature, expiry and type of the token and that its session is not revoked.
+func (s *AuthService) authenticate(
+	ctx context.Context,
+	accessToken string,
+) (*tokenClaims, error) {
+	if accessToken == "" {
+		return nil, fmt.Errorf("%w: missing access token", ErrUnauthorized)
+	}
+
+	claims, err := s.parseJWTToken(accessToken, "access")
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.UserId == "" || claims.SessionId == "" {
+		return nil, fmt.Errorf("%w: token has no user or session", ErrUnauthorized)
+	}
+
+	session, err := s.repository.IsSessionActive(ctx, &dbtypes.IsSessionActiveRequest{
+		FamilyId: claims.SessionId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error checking session: %v", s.handleDBError(err), err)
+	}
+
+	if !session.Active {
+		return nil, fmt.Errorf("%w: session revoked", ErrUnauthorized)
+	}
+
+	return claims, nil
+}
+
+func (s *AuthService) getStoredRefreshToken(
+	ctx context.Context,
+	refreshToken string,
+) (*dbtypes.RefreshToken, error) {
+	if _, err := s.parseJWTToken(refreshToken, "refresh"); err != nil {
+		return nil, err
+	}
+
+	stored, err := s.repository.GetRefreshTokenByHash(ctx, &dbtypes.GetRefreshTokenByHashRequest{
+		TokenHash: hashToken(refreshToken),
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: unknown refresh token", ErrUnauthorized)
+		}
+
+		return nil, fmt.Errorf("%w: error getting refresh token: %v", s.handleDBError(err), err)
+	}
+
+	return stored.Token, nil
+}
+
+// startSession issues the tokens of a new session. A login also records a
+// user.logged_in event with the session.
+func (s *AuthService) startSession(
+	ctx context.Context,
+	subject *tokenSubject,
+	login bool,
+) (*jwtTokens, error) {
+	subject.sessionId = uuid.NewString()
+
+	tokens, err := s.generateJWTTokens(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.repository.CreateRefreshToken(ctx, &dbtypes.CreateRefreshTokenRequest{
+		UserId:    subject.userId,
+		FamilyId:  subject.sessionId,
+		ClientId:  subject.clientId,
+		TokenHash: hashToken(tokens.refreshToken),
+		ExpiresAt: tokens.refreshExpiresAt,
+		Login:     login,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error saving refresh token: %v", s.handleDBError(err), err)
+	}
+
+	return tokens, nil
+}
+
+func (s *AuthService) revokeFamily(ctx context.Context, familyId string) error {
+	err := s.repository.RevokeRefreshTokenFamily(ctx, &dbtypes.RevokeRefreshTokenFamilyRequest{
+		FamilyId: familyId,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: error revoking refresh token family: %v", s.handleDBError(err), err)
+	}
+
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
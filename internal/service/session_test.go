@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/constants"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+func login(t *testing.T, s *AuthService, email string) *rmodel.LoginResponse {
+	t.Helper()
+
+	resp, err := s.Login(context.Background(), &rmodel.LoginRequest{
+		Email:    email,
+		Password: testPassword,
+	})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	return resp
+}
+
+func TestRefreshTokensRotates(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository()
+	user := repo.addUser(t, "01", constants.UserStudentRole)
+	s := newTestService(t, repo)
+
+	session := login(t, s, user.Email)
+
+	resp, err := s.RefreshTokens(ctx, &rmodel.RefreshTokensRequest{RefreshToken: session.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshTokens() error = %v", err)
+	}
+
+	if resp.RefreshToken == session.RefreshToken {
+		t.Errorf("RefreshTokens() returned the same refresh token")
+	}
+
+	if used := repo.tokens[hashToken(session.RefreshToken)]; used.UsedAt == nil {
+		t.Errorf("RefreshTokens() did not mark the old token used")
+	}
+
+	rotated, ok := repo.tokens[hashToken(resp.RefreshToken)]
+	if !ok {
+		t.Fatalf("RefreshTokens() did not store the new token")
+	}
+
+	if old := repo.tokens[hashToken(session.RefreshToken)]; rotated.FamilyId != old.FamilyId {
+		t.Errorf("rotated token family = %q, want %q", rotated.FamilyId, old.FamilyId)
+	}
+
+	if _, err := s.RefreshTokens(ctx, &rmodel.RefreshTokensRequest{RefreshToken: resp.RefreshToken}); err != nil {
+		t.Errorf("RefreshTokens() with the rotated token error = %v", err)
+	}
+}
+
+func TestRefreshTokensReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository()
+	user := repo.addUser(t, "01", constants.UserStudentRole)
+	s := newTestService(t, repo)
+
+	session := login(t, s, user.Email)
+
+	resp, err := s.RefreshTokens(ctx, &rmodel.RefreshTokensRequest{RefreshToken: session.RefreshToken})
+	if err != nil {
+		t.Fatalf("RefreshTokens() error = %v", err)
+	}
+
+	_, err = s.RefreshTokens(ctx, &rmodel.RefreshTokensRequest{RefreshToken: session.RefreshToken})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("RefreshTokens() with a used token error = %v, want %v", err, ErrUnauthorized)
+	}
+
+	familyId := repo.tokens[hashToken(session.RefreshToken)].FamilyId
+	if !repo.revokedFamilies[familyId] {
+		t.Errorf("reuse did not revoke the token family")
+	}
+
+	// the token issued before the reuse belongs to the same session
+	_, err = s.RefreshTokens(ctx, &rmodel.RefreshTokensRequest{RefreshToken: resp.RefreshToken})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("RefreshTokens() after reuse error = %v, want %v", err, ErrUnauthorized)
+	}
+
+	if _, err := s.Authenticate(ctx, resp.AccessToken); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Authenticate() after reuse error = %v, want %v", err, ErrUnauthorized)
+	}
+}
+
+func TestRefreshTokensConcurrentRotationIsReuse(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeRepository()
+	user := repo.addUser(t, "01", constants.UserStudentRole)
+	s := newTestService(t, repo)
+
+	session := login(t, s, user.Email)
+	stored := repo.tokens[hashToken(session.RefreshToken)]
+
+	// another request rotates the token after it was read
+	repo.beforeRotate = func() {
+		now := time.Now().UTC()
+		stored.UsedAt = &now
+	}
+
+	_, err := s.RefreshTokens(ctx, &rmodel.RefreshTokensRequest{RefreshToken: session.RefreshToken})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("RefreshTokens() error = %v, want %v", err, ErrUnauthorized)
+	}
+
+	if !repo.revokedFamilies[stored.FamilyId] {
+		t.Errorf("a lost rotation race did not revoke the token family")
+	}
+}
+
+func TestRefreshTokensRefused(t *testing.T) {
+	tests := []struct {
+		name  string
+		token func(t *testing.T, s *AuthService, repo *fakeRepository) string
+		want  error
+	}{
+		{
+			name: "revoked",
+			want: ErrUnauthorized,
+			token: func(t *testing.T, s *AuthService, repo *fakeRepository) string {
+				session := login(t, s, repo.addUser(t, "01", constants.UserStudentRole).Email)
+				if err := s.Logout(context.Background(), &rmodel.LogoutRequest{RefreshToken: session.RefreshToken}); err != nil {
+					t.Fatalf("Logout() error = %v", err)
+				}
+
+				return session.RefreshToken
+			},
+		},
+		{
+			name: "expired",
+			want: ErrUnauthorized,
+			token: func(t *testing.T, s *AuthService, repo *fakeRepository) string {
+				session := login(t, s, repo.addUser(t, "01", constants.UserStudentRole).Email)
+				repo.tokens[hashToken(session.RefreshToken)].ExpiresAt = time.Now().UTC().Add(-time.Minute)
+
+				return session.RefreshToken
+			},
+		},
+		{
+			name: "unknown",
+			want: ErrUnauthorized,
+			token: func(t *testing.T, s *AuthService, repo *fakeRepository) string {
+				session := login(t, s, repo.addUser(t, "01", constants.UserStudentRole).Email)
+				delete(repo.tokens, hashToken(session.RefreshToken))
+
+				return session.RefreshToken
+			},
+		},
+		{
+			name: "access token",
+			want: ErrUnauthorized,
+			token: func(t *testing.T, s *AuthService, repo *fakeRepository) string {
+				return login(t, s, repo.addUser(t, "01", constants.UserStudentRole).Email).AccessToken
+			},
+		},
+		{
+			name: "blocked user",
+			want: ErrForbidden,
+			token: func(t *testing.T, s *AuthService, repo *fakeRepository) string {
+				user := repo.addUser(t, "01", constants.UserStudentRole)
+				session := login(t, s, user.Email)
+				user.Status = constants.UserStatusBanned
+
+				return session.RefreshToken
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeRepository()
+			s := newTestService(t, repo)
+
+			_, err := s.RefreshTokens(context.Background(), &rmodel.RefreshTokensRequest{
+				RefreshToken: tt.token(t, s, repo),
+			})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("RefreshTokens() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/constants"
+)
+
+var knownStatuses = map[string]bool{
+	constants.UserStatusActive:    true,
+	constants.UserStatusSuspended: true,
+	constants.UserStatusBanned:    true,
+	constants.UserStatusGraduated: true,
+}
+
+// effectiveStatus is the status of the user at now: a status whose expiry has
+// passed no longer applies and the user is active again.
+func effectiveStatus(status string, expiresAt *time.Time, now time.Time) string {
+	if status == "" {
+		return constants.UserStatusActive
+	}
+
+	if status != constants.UserStatusActive && expiresAt != nil && !now.Before(*expiresAt) {
+		return constants.UserStatusActive
+	}
+
+	return status
+}
+
+// checkUserActive refuses users that are suspended, banned or graduated.
+func checkUserActive(status string, expiresAt *time.Time) error {
+	status = effectiveStatus(status, expiresAt, time.Now().UTC())
+	if status != constants.UserStatusActive {
+		return fmt.Errorf("%w: account is %s", ErrForbidden, status)
+	}
+
+	return nil
+}
@@ -3,9 +3,11 @@ package auth
 import (
 	"context"
 	"fmt"
+	"time"
 
 	dbtypes "github.com/dormitory-life/auth/internal/database/types"
 	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+	"github.com/google/uuid"
 )
 
 func (s *AuthService) GetUserInfoById(
@@ -16,6 +18,10 @@ func (s *AuthService) GetUserInfoById(
 		return nil, ErrBadRequest
 	}
 
+	if err := checkUserId(request.UserId); err != nil {
+		return nil, err
+	}
+
 	resp, err := s.repository.GetUserById(ctx, &dbtypes.GetUserInfoByIdRequest{
 		Id: request.UserId,
 	})
@@ -28,8 +34,19 @@ func (s *AuthService) GetUserInfoById(
 			UserId:      resp.UserId,
 			DormitoryId: resp.DormitoryId,
 			Role:        resp.Role,
+			Status:      effectiveStatus(resp.Status, resp.StatusExpiresAt, time.Now().UTC()),
 		},
 	}
 
 	return result, nil
 }
+
+// checkUserId refuses ids that are not UUIDs, the database would fail to
+// cast them.
+func checkUserId(userId string) error {
+	if _, err := uuid.Parse(userId); err != nil {
+		return fmt.Errorf("%w: malformed user id %q", ErrBadRequest, userId)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+const (
+	userChangesPageSize     = 100
+	userChangesPollInterval = time.Second
+)
+
+// WatchUserChanges passes changes from the change log to send, oldest first,
+// until ctx is done or send fails. It polls the log while the watcher has
+// caught up.
+func (s *AuthService) WatchUserChanges(
+	ctx context.Context,
+	request *rmodel.WatchUserChangesRequest,
+	send func(change *rmodel.UserChange) error,
+) error {
+	if request == nil || send == nil {
+		return ErrBadRequest
+	}
+
+	afterId, err := s.userChangesStart(ctx, request.Cursor)
+	if err != nil {
+		return err
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		resp, err := s.repository.ListUserChanges(ctx, &dbtypes.ListUserChangesRequest{
+			AfterId:     afterId,
+			DormitoryId: request.DormitoryId,
+			Limit:       userChangesPageSize,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: error listing user changes: %v", s.handleDBError(err), err)
+		}
+
+		for i := range resp.Changes {
+			if err := send(new(rmodel.UserChange).From(&resp.Changes[i])); err != nil {
+				return err
+			}
+
+			afterId = resp.Changes[i].Id
+		}
+
+		// a full page means the watcher is behind, read on without waiting
+		if len(resp.Changes) == userChangesPageSize {
+			timer.Reset(0)
+		} else {
+			timer.Reset(userChangesPollInterval)
+		}
+	}
+}
+
+func (s *AuthService) userChangesStart(ctx context.Context, cursor string) (int64, error) {
+	if cursor != "" {
+		afterId, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || afterId < 0 {
+			return 0, fmt.Errorf("%w: invalid cursor %q", ErrBadRequest, cursor)
+		}
+
+		return afterId, nil
+	}
+
+	resp, err := s.repository.GetLastUserChangeId(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%w: error getting last user change: %v", s.handleDBError(err), err)
+	}
+
+	return resp.Id, nil
+}
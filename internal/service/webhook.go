@@ -0,0 +1,322 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	dberrors "github.com/dormitory-life/auth/internal/database/errors"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	rmodel "github.com/dormitory-life/auth/internal/server/request_models"
+)
+
+var webhookDeliveryStatuses = map[string]bool{
+	dbtypes.WebhookDeliveryStatusPending:   true,
+	dbtypes.WebhookDeliveryStatusDelivered: true,
+	dbtypes.WebhookDeliveryStatusDead:      true,
+}
+
+// CreateWebhook subscribes a URL to the events of the dormitory. The secret
+// that signs the deliveries is generated here and returned only once.
+func (s *AuthService) CreateWebhook(
+	ctx context.Context,
+	request *rmodel.CreateWebhookRequest,
+) (*rmodel.Webhook, error) {
+	if request == nil || request.DormitoryId == "" || request.ActorId == "" {
+		return nil, ErrBadRequest
+	}
+
+	if err := s.validateWebhookURL(ctx, request.URL); err != nil {
+		return nil, err
+	}
+
+	eventTypes, err := normalizeEventTypes(request.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.repository.CreateWebhookSubscription(ctx, &dbtypes.CreateWebhookSubscriptionRequest{
+		DormitoryId: request.DormitoryId,
+		URL:         request.URL,
+		Secret:      secret,
+		EventTypes:  eventTypes,
+		CreatedBy:   request.ActorId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error creating webhook: %v", s.handleDBError(err), err)
+	}
+
+	result := new(rmodel.Webhook).From(resp.Subscription)
+	result.Secret = resp.Subscription.Secret
+
+	return result, nil
+}
+
+func (s *AuthService) ListWebhooks(
+	ctx context.Context,
+	request *rmodel.ListWebhooksRequest,
+) (*rmodel.ListWebhooksResponse, error) {
+	if request == nil || request.DormitoryId == "" {
+		return nil, ErrBadRequest
+	}
+
+	resp, err := s.repository.ListWebhookSubscriptions(ctx, &dbtypes.ListWebhookSubscriptionsRequest{
+		DormitoryId: request.DormitoryId,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: error listing webhooks: %v", s.handleDBError(err), err)
+	}
+
+	result := &rmodel.ListWebhooksResponse{
+		Webhooks: make([]*rmodel.Webhook, 0, len(resp.Subscriptions)),
+	}
+
+	for i := range resp.Subscriptions {
+		result.Webhooks = append(result.Webhooks, new(rmodel.Webhook).From(&resp.Subscriptions[i]))
+	}
+
+	return result, nil
+}
+
+func (s *AuthService) UpdateWebhook(
+	ctx context.Context,
+	request *rmodel.UpdateWebhookRequest,
+) (*rmodel.Webhook, error) {
+	if request == nil || request.DormitoryId == "" || request.WebhookId == "" {
+		return nil, ErrBadRequest
+	}
+
+	if request.URL == nil && request.EventTypes == nil && request.Active == nil {
+		return nil, fmt.Errorf("%w: nothing to update", ErrBadRequest)
+	}
+
+	updateRequest := &dbtypes.UpdateWebhookSubscriptionRequest{
+		Id:          request.WebhookId,
+		DormitoryId: request.DormitoryId,
+		URL:         request.URL,
+		Active:      request.Active,
+	}
+
+	if request.URL != nil {
+		if err := s.validateWebhookURL(ctx, *request.URL); err != nil {
+			return nil, err
+		}
+	}
+
+	if request.EventTypes != nil {
+		eventTypes, err := normalizeEventTypes(*request.EventTypes)
+		if err != nil {
+			return nil, err
+		}
+
+		updateRequest.EventTypes = eventTypes
+	}
+
+	resp, err := s.repository.UpdateWebhookSubscription(ctx, updateRequest)
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: webhook not found", ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error updating webhook: %v", s.handleDBError(err), err)
+	}
+
+	return new(rmodel.Webhook).From(resp.Subscription), nil
+}
+
+// RotateWebhookSecret replaces the secret of the webhook and returns the new
+// one. Deliveries not sent yet are signed with it, including retries.
+func (s *AuthService) RotateWebhookSecret(
+	ctx context.Context,
+	request *rmodel.RotateWebhookSecretRequest,
+) (*rmodel.Webhook, error) {
+	if request == nil || request.DormitoryId == "" || request.WebhookId == "" {
+		return nil, ErrBadRequest
+	}
+
+	secret, err := generateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.repository.UpdateWebhookSubscription(ctx, &dbtypes.UpdateWebhookSubscriptionRequest{
+		Id:          request.WebhookId,
+		DormitoryId: request.DormitoryId,
+		Secret:      &secret,
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: webhook not found", ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error rotating webhook secret: %v", s.handleDBError(err), err)
+	}
+
+	result := new(rmodel.Webhook).From(resp.Subscription)
+	result.Secret = resp.Subscription.Secret
+
+	return result, nil
+}
+
+func (s *AuthService) DeleteWebhook(
+	ctx context.Context,
+	request *rmodel.DeleteWebhookRequest,
+) error {
+	if request == nil || request.DormitoryId == "" || request.WebhookId == "" {
+		return ErrBadRequest
+	}
+
+	err := s.repository.DeleteWebhookSubscription(ctx, &dbtypes.DeleteWebhookSubscriptionRequest{
+		Id:          request.WebhookId,
+		DormitoryId: request.DormitoryId,
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return fmt.Errorf("%w: webhook not found", ErrNotFound)
+		}
+
+		return fmt.Errorf("%w: error deleting webhook: %v", s.handleDBError(err), err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) ListWebhookDeliveries(
+	ctx context.Context,
+	request *rmodel.ListWebhookDeliveriesRequest,
+) (*rmodel.ListWebhookDeliveriesResponse, error) {
+	if request == nil || request.DormitoryId == "" || request.WebhookId == "" {
+		return nil, ErrBadRequest
+	}
+
+	if request.Status != "" && !webhookDeliveryStatuses[request.Status] {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrBadRequest, request.Status)
+	}
+
+	limit := request.Limit
+	if limit == 0 {
+		limit = defaultUsersPageSize
+	}
+
+	if limit > maxUsersPageSize {
+		limit = maxUsersPageSize
+	}
+
+	resp, err := s.repository.ListWebhookDeliveries(ctx, &dbtypes.ListWebhookDeliveriesRequest{
+		SubscriptionId: request.WebhookId,
+		DormitoryId:    request.DormitoryId,
+		Status:         request.Status,
+		Limit:          limit,
+		Offset:         request.Offset,
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: webhook not found", ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error listing webhook deliveries: %v", s.handleDBError(err), err)
+	}
+
+	result := &rmodel.ListWebhookDeliveriesResponse{
+		Deliveries: make([]*rmodel.WebhookDelivery, 0, len(resp.Deliveries)),
+		Limit:      limit,
+		Offset:     request.Offset,
+	}
+
+	for i := range resp.Deliveries {
+		result.Deliveries = append(result.Deliveries, new(rmodel.WebhookDelivery).From(&resp.Deliveries[i]))
+	}
+
+	return result, nil
+}
+
+func (s *AuthService) ListWebhookDeliveryAttempts(
+	ctx context.Context,
+	request *rmodel.ListWebhookDeliveryAttemptsRequest,
+) (*rmodel.ListWebhookDeliveryAttemptsResponse, error) {
+	if request == nil || request.DormitoryId == "" || request.WebhookId == "" || request.DeliveryId == "" {
+		return nil, ErrBadRequest
+	}
+
+	resp, err := s.repository.ListWebhookDeliveryAttempts(ctx, &dbtypes.ListWebhookDeliveryAttemptsRequest{
+		DeliveryId:     request.DeliveryId,
+		SubscriptionId: request.WebhookId,
+		DormitoryId:    request.DormitoryId,
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return nil, fmt.Errorf("%w: webhook delivery not found", ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("%w: error listing webhook delivery attempts: %v", s.handleDBError(err), err)
+	}
+
+	result := &rmodel.ListWebhookDeliveryAttemptsResponse{
+		Attempts: make([]*rmodel.WebhookDeliveryAttempt, 0, len(resp.Attempts)),
+	}
+
+	for i := range resp.Attempts {
+		result.Attempts = append(result.Attempts, new(rmodel.WebhookDeliveryAttempt).From(&resp.Attempts[i]))
+	}
+
+	return result, nil
+}
+
+// RetryWebhookDelivery gives a dead delivery a fresh set of attempts.
+func (s *AuthService) RetryWebhookDelivery(
+	ctx context.Context,
+	request *rmodel.RetryWebhookDeliveryRequest,
+) error {
+	if request == nil || request.DormitoryId == "" || request.WebhookId == "" || request.DeliveryId == "" {
+		return ErrBadRequest
+	}
+
+	err := s.repository.RetryWebhookDelivery(ctx, &dbtypes.RetryWebhookDeliveryRequest{
+		DeliveryId:     request.DeliveryId,
+		SubscriptionId: request.WebhookId,
+		DormitoryId:    request.DormitoryId,
+		Now:            time.Now().UTC(),
+	})
+	if err != nil {
+		if errors.Is(err, dberrors.ErrNotFound) {
+			return fmt.Errorf("%w: webhook delivery not found", ErrNotFound)
+		}
+
+		return fmt.Errorf("%w: error retrying webhook delivery: %v", s.handleDBError(err), err)
+	}
+
+	return nil
+}
+
+// validateWebhookURL refuses URLs that are malformed or point into the
+// internal network.
+func (s *AuthService) validateWebhookURL(ctx context.Context, rawURL string) error {
+	if err := s.webhookTargets.CheckURL(ctx, rawURL); err != nil {
+		return fmt.Errorf("%w: %v", ErrBadRequest, err)
+	}
+
+	return nil
+}
+
+// normalizeEventTypes checks the event types and drops repeats.
+func normalizeEventTypes(eventTypes []string) ([]string, error) {
+	result := make([]string, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if !slices.Contains(dbtypes.OutboxEventTypes, eventType) {
+			return nil, fmt.Errorf("%w: unknown event type %q", ErrBadRequest, eventType)
+		}
+
+		if !slices.Contains(result, eventType) {
+			result = append(result, eventType)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,89 @@
+package sweeper
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/database"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+const defaultInterval = time.Minute
+
+// Sweeper periodically starts and expires time-bounded grants. Access checks
+// honour grant periods on their own; the sweeper records the role changes
+// and revokes the sessions of users whose grants ended.
+type Sweeper struct {
+	repository database.Repository
+	logger     *slog.Logger
+	interval   time.Duration
+}
+
+type Config struct {
+	Repository database.Repository
+	Logger     *slog.Logger
+	Interval   time.Duration
+}
+
+func New(cfg Config) *Sweeper {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	return &Sweeper{
+		repository: cfg.Repository,
+		logger:     cfg.Logger,
+		interval:   interval,
+	}
+}
+
+// Run sweeps once at start and then every interval until ctx is done.
+func (s *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Sweeper) sweep(ctx context.Context) {
+	resp, err := s.repository.SweepGrants(ctx, &dbtypes.SweepGrantsRequest{
+		Now: time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.Error("error sweeping grants", slog.String("error", err.Error()))
+		return
+	}
+
+	for _, grant := range resp.StartedRoleGrants {
+		s.logger.Info("role grant started",
+			slog.String("grant_id", grant.Id),
+			slog.String("user_id", grant.UserId),
+			slog.String("role", grant.Role),
+		)
+	}
+
+	for _, grant := range resp.ExpiredRoleGrants {
+		s.logger.Info("role grant expired",
+			slog.String("grant_id", grant.Id),
+			slog.String("user_id", grant.UserId),
+			slog.String("role", grant.Role),
+		)
+	}
+
+	for _, grant := range resp.ExpiredPermissionGrants {
+		s.logger.Info("permission grant expired",
+			slog.String("user_id", grant.UserId),
+			slog.String("permission", grant.Permission),
+		)
+	}
+}
@@ -0,0 +1,237 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/database"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/dormitory-life/auth/internal/worker"
+)
+
+const (
+	defaultInterval    = time.Second
+	defaultBatchSize   = 50
+	defaultMaxAttempts = 10
+	defaultTimeout     = 10 * time.Second
+
+	// lease keeps a claimed delivery away from other dispatchers
+	lease = 5 * time.Minute
+)
+
+var retryBackoff = worker.Backoff{Min: 10 * time.Second, Max: time.Hour}
+
+// Headers of a delivery. The signature is the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the secret of the webhook, prefixed with
+// "sha256=".
+const (
+	HeaderDeliveryId = "X-Webhook-Id"
+	HeaderEventId    = "X-Webhook-Event-Id"
+	HeaderEventType  = "X-Webhook-Event-Type"
+	HeaderTimestamp  = "X-Webhook-Timestamp"
+	HeaderSignature  = "X-Webhook-Signature"
+)
+
+// Dispatcher delivers queued webhook deliveries. A delivery that failed is
+// retried with doubling delays; after MaxAttempts attempts it is dead.
+type Dispatcher struct {
+	repository  database.Repository
+	logger      *slog.Logger
+	client      *http.Client
+	interval    time.Duration
+	batchSize   uint64
+	maxAttempts int
+}
+
+type Config struct {
+	Repository  database.Repository
+	Logger      *slog.Logger
+	Interval    time.Duration
+	BatchSize   uint64
+	MaxAttempts int
+	// Timeout limits one delivery, the answer included.
+	Timeout time.Duration
+	// Targets decides which addresses deliveries may connect to. Nil
+	// allows public addresses only.
+	Targets *TargetPolicy
+}
+
+func NewDispatcher(cfg Config) *Dispatcher {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultBatchSize
+	}
+
+	maxAttempts := cfg.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	targets := cfg.Targets
+	if targets == nil {
+		targets = &TargetPolicy{}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// a proxy would be the only address the dialer gets to check
+	transport.Proxy = nil
+	transport.DialContext = targets.DialContext(timeout)
+
+	return &Dispatcher{
+		repository: cfg.Repository,
+		logger:     cfg.Logger,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// a redirect is an answer of its own, not a place to send
+			// the event to
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Run delivers due deliveries every interval until ctx is done. A full batch
+// is followed by the next one right away.
+func (d *Dispatcher) Run(ctx context.Context) {
+	worker.RunBatches(ctx, d.interval, d.dispatch)
+}
+
+// dispatch delivers one batch and reports whether more may be due.
+func (d *Dispatcher) dispatch(ctx context.Context) bool {
+	now := time.Now().UTC()
+
+	resp, err := d.repository.ClaimWebhookDeliveries(ctx, &dbtypes.ClaimWebhookDeliveriesRequest{
+		Now:   now,
+		Lease: lease,
+		Limit: d.batchSize,
+	})
+	if err != nil {
+		d.logger.Error("error claiming webhook deliveries", slog.String("error", err.Error()))
+		return false
+	}
+
+	deadline := worker.LeaseDeadline(now, lease)
+
+	for i := range resp.Deliveries {
+		if ctx.Err() != nil || time.Now().UTC().After(deadline) {
+			return false
+		}
+
+		d.deliver(ctx, &resp.Deliveries[i])
+	}
+
+	return uint64(len(resp.Deliveries)) == d.batchSize
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *dbtypes.ClaimedWebhookDelivery) {
+	attemptedAt := time.Now().UTC()
+
+	statusCode, err := d.send(ctx, delivery, attemptedAt)
+
+	record := &dbtypes.RecordWebhookDeliveryAttemptRequest{
+		DeliveryId:  delivery.Id,
+		StatusCode:  statusCode,
+		Duration:    time.Since(attemptedAt),
+		AttemptedAt: attemptedAt,
+		Status:      dbtypes.WebhookDeliveryStatusDelivered,
+	}
+
+	if err != nil {
+		record.Error = err.Error()
+		record.Status = dbtypes.WebhookDeliveryStatusDead
+
+		if delivery.Attempts < d.maxAttempts {
+			record.Status = dbtypes.WebhookDeliveryStatusPending
+			record.NextAttemptAt = time.Now().UTC().Add(retryBackoff.Delay(delivery.Attempts))
+		}
+
+		d.logger.Warn("webhook delivery failed",
+			slog.String("error", err.Error()),
+			slog.String("delivery_id", delivery.Id),
+			slog.String("subscription_id", delivery.SubscriptionId),
+			slog.Int("attempts", delivery.Attempts),
+			slog.String("status", record.Status),
+		)
+	}
+
+	// the attempt is logged even when the request was cut short by ctx
+	err = d.repository.RecordWebhookDeliveryAttempt(context.WithoutCancel(ctx), record)
+	if err != nil {
+		d.logger.Error("error recording webhook delivery attempt",
+			slog.String("error", err.Error()),
+			slog.String("delivery_id", delivery.Id),
+		)
+	}
+}
+
+// send posts the delivery and returns the status code of the answer, nil
+// when there was none.
+func (d *Dispatcher) send(
+	ctx context.Context,
+	delivery *dbtypes.ClaimedWebhookDelivery,
+	attemptedAt time.Time,
+) (*int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build webhook request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(attemptedAt.Unix(), 10)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDeliveryId, delivery.Id)
+	req.Header.Set(HeaderEventId, delivery.EventId)
+	req.Header.Set(HeaderEventType, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, "sha256="+sign(delivery.Secret, timestamp, delivery.Body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send webhook: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	// drained so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	statusCode := resp.StatusCode
+	if statusCode < 200 || statusCode >= 300 {
+		return &statusCode, fmt.Errorf("webhook answered with status %d", statusCode)
+	}
+
+	return &statusCode, nil
+}
+
+func sign(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+)
+
+func TestSign(t *testing.T) {
+	// hex HMAC-SHA256 of `1700000000.{"id":"1"}` keyed with "whsec_test"
+	const want = "11bf4466ea17c3df3fd743af0b435368e16b7a05eb8eced85e8c4670767bdec5"
+
+	if got := sign("whsec_test", "1700000000", []byte(`{"id":"1"}`)); got != want {
+		t.Errorf("sign() = %s, want %s", got, want)
+	}
+
+	if sign("other", "1700000000", []byte(`{"id":"1"}`)) == want {
+		t.Error("sign() does not depend on the secret")
+	}
+
+	if sign("whsec_test", "1700000001", []byte(`{"id":"1"}`)) == want {
+		t.Error("sign() does not depend on the timestamp")
+	}
+}
+
+func TestDispatcherSend(t *testing.T) {
+	body := []byte(`{"type":"user.registered"}`)
+	attemptedAt := time.Unix(1700000000, 0)
+
+	var got *http.Request
+	var gotBody []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	targets, err := NewTargetPolicy([]string{"127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher := NewDispatcher(Config{Targets: targets})
+
+	delivery := &dbtypes.ClaimedWebhookDelivery{
+		WebhookDelivery: dbtypes.WebhookDelivery{
+			Id:        "delivery",
+			EventId:   "event",
+			EventType: "user.registered",
+			Body:      body,
+		},
+		URL:    server.URL,
+		Secret: "whsec_test",
+	}
+
+	statusCode, err := dispatcher.send(context.Background(), delivery, attemptedAt)
+	if err != nil {
+		t.Fatalf("send() error = %v", err)
+	}
+
+	if statusCode == nil || *statusCode != http.StatusAccepted {
+		t.Fatalf("send() status = %v, want %d", statusCode, http.StatusAccepted)
+	}
+
+	headers := map[string]string{
+		HeaderDeliveryId: "delivery",
+		HeaderEventId:    "event",
+		HeaderEventType:  "user.registered",
+		HeaderTimestamp:  "1700000000",
+		HeaderSignature:  "sha256=" + sign("whsec_test", "1700000000", body),
+	}
+
+	for header, want := range headers {
+		if value := got.Header.Get(header); value != want {
+			t.Errorf("header %s = %q, want %q", header, value, want)
+		}
+	}
+
+	if string(gotBody) != string(body) {
+		t.Errorf("body = %s, want %s", gotBody, body)
+	}
+}
+
+func TestDispatcherSendRefusesInternalTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("an internal target was reached")
+	}))
+	defer server.Close()
+
+	dispatcher := NewDispatcher(Config{})
+
+	delivery := &dbtypes.ClaimedWebhookDelivery{
+		URL: server.URL,
+	}
+
+	statusCode, err := dispatcher.send(context.Background(), delivery, time.Now())
+	if err == nil {
+		t.Fatal("send() to a loopback address succeeded")
+	}
+
+	if statusCode != nil {
+		t.Errorf("send() status = %d, want none", *statusCode)
+	}
+}
@@ -0,0 +1,44 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dormitory-life/auth/internal/database"
+	dbtypes "github.com/dormitory-life/auth/internal/database/types"
+	"github.com/dormitory-life/auth/internal/publisher"
+)
+
+// Enqueuer is the publisher that hands outbox events to the webhooks of the
+// dormitory of the event. The dispatcher delivers them from there.
+type Enqueuer struct {
+	repository database.Repository
+}
+
+func NewEnqueuer(repository database.Repository) *Enqueuer {
+	return &Enqueuer{
+		repository: repository,
+	}
+}
+
+func (e *Enqueuer) Publish(ctx context.Context, event *publisher.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	err = e.repository.EnqueueWebhookDeliveries(ctx, &dbtypes.EnqueueWebhookDeliveriesRequest{
+		EventId:     event.Id,
+		EventType:   event.Type,
+		DormitoryId: event.DormitoryId,
+		Body:        body,
+		Now:         time.Now().UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enqueue webhook deliveries: %w", err)
+	}
+
+	return nil
+}
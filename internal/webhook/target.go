@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrForbiddenTarget = errors.New("webhook target is not allowed")
+
+// TargetPolicy keeps webhooks away from the internal network: loopback,
+// private, link-local, multicast and unspecified addresses are refused
+// unless the host or its network is on the allowlist. The zero value
+// allows public addresses only.
+type TargetPolicy struct {
+	hosts    map[string]bool
+	networks []netip.Prefix
+}
+
+// NewTargetPolicy builds a policy from an allowlist of host names, IP
+// addresses and CIDR ranges that webhooks may reach even though they are
+// internal.
+func NewTargetPolicy(allowlist []string) (*TargetPolicy, error) {
+	policy := &TargetPolicy{
+		hosts: make(map[string]bool),
+	}
+
+	for _, entry := range allowlist {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allowed webhook network %q: %w", entry, err)
+			}
+
+			policy.networks = append(policy.networks, prefix.Masked())
+			continue
+		}
+
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			policy.networks = append(policy.networks, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+
+		policy.hosts[strings.ToLower(entry)] = true
+	}
+
+	return policy, nil
+}
+
+// CheckURL checks that a webhook URL is an absolute http or https URL
+// without credentials whose host resolves to allowed addresses only. The
+// dialer checks the addresses again on every delivery.
+func (p *TargetPolicy) CheckURL(ctx context.Context, rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrForbiddenTarget)
+	}
+
+	if parsed.User != nil {
+		return fmt.Errorf("%w: url must not contain credentials", ErrForbiddenTarget)
+	}
+
+	host := parsed.Hostname()
+	if p.allowedHost(host) {
+		return nil
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.checkAddr(addr)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve %s", ErrForbiddenTarget, host)
+	}
+
+	for _, addr := range addrs {
+		if err := p.checkAddr(addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DialContext dials like net.Dialer but refuses connections to addresses the
+// policy does not allow. The check runs on the address actually dialed, so
+// a host that resolves differently at delivery time is still refused.
+// Allowlisted host names skip the check.
+func (p *TargetPolicy) DialContext(timeout time.Duration) func(ctx context.Context, network, address string) (net.Conn, error) {
+	checked := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrForbiddenTarget, err)
+			}
+
+			return p.checkAddr(addrPort.Addr())
+		},
+	}
+
+	trusted := &net.Dialer{
+		Timeout: timeout,
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err == nil && p.allowedHost(host) {
+			return trusted.DialContext(ctx, network, address)
+		}
+
+		return checked.DialContext(ctx, network, address)
+	}
+}
+
+func (p *TargetPolicy) allowedHost(host string) bool {
+	return p.hosts[strings.ToLower(host)]
+}
+
+func (p *TargetPolicy) checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+
+	for _, network := range p.networks {
+		if network.Contains(addr) {
+			return nil
+		}
+	}
+
+	if isInternalAddr(addr) {
+		return fmt.Errorf("%w: %s is an internal address", ErrForbiddenTarget, addr)
+	}
+
+	return nil
+}
+
+func isInternalAddr(addr netip.Addr) bool {
+	return addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() ||
+		// 0.0.0.0/8 reaches the local host on Linux
+		(addr.Is4() && addr.As4()[0] == 0)
+}
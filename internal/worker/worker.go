@@ -0,0 +1,52 @@
+// Package worker holds what the background workers claiming rows under a
+// lease have in common: the batch loop, the lease deadline and the delays
+// between retries.
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// RunBatches calls batch every interval until ctx is done. A batch reporting
+// that more may be due is followed by the next one right away.
+func RunBatches(ctx context.Context, interval time.Duration, batch func(ctx context.Context) bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil && batch(ctx) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// LeaseDeadline is when a batch claimed at claimedAt stops starting work. A
+// lease keeps the claimed rows away from other workers, and stopping after
+// half of it means work in flight cannot outlive the lease; the rest is
+// claimed again once the lease is over.
+func LeaseDeadline(claimedAt time.Time, lease time.Duration) time.Time {
+	return claimedAt.Add(lease / 2)
+}
+
+// Backoff doubles the delay before the next attempt with every failed
+// attempt, from Min up to Max.
+type Backoff struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// Delay is the wait after the given number of failed attempts.
+func (b Backoff) Delay(attempts int) time.Duration {
+	delay := b.Min
+	for i := 1; i < attempts && delay < b.Max; i++ {
+		delay *= 2
+	}
+
+	return min(delay, b.Max)
+}
@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	backoff := Backoff{Min: 10 * time.Second, Max: time.Hour}
+
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: 10 * time.Second},
+		{attempts: 1, want: 10 * time.Second},
+		{attempts: 2, want: 20 * time.Second},
+		{attempts: 3, want: 40 * time.Second},
+		{attempts: 9, want: 2560 * time.Second},
+		{attempts: 10, want: time.Hour},
+		{attempts: 1000, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := backoff.Delay(tt.attempts); got != tt.want {
+			t.Errorf("Delay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestLeaseDeadline(t *testing.T) {
+	claimedAt := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	got := LeaseDeadline(claimedAt, 5*time.Minute)
+	if want := claimedAt.Add(150 * time.Second); !got.Equal(want) {
+		t.Errorf("LeaseDeadline() = %v, want %v", got, want)
+	}
+}
+
+func TestRunBatchesRepeatsFullBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		// a long interval, so every call after the first comes from a batch
+		// asking for more
+		RunBatches(ctx, time.Hour, func(ctx context.Context) bool {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+
+			return true
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunBatches did not return after ctx was canceled")
+	}
+
+	if calls != 3 {
+		t.Errorf("batch called %d times, want 3", calls)
+	}
+}
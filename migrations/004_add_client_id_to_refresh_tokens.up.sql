@@ -0,0 +1,2 @@
+ALTER TABLE refresh_tokens
+ADD COLUMN IF NOT EXISTS client_id VARCHAR(32) NOT NULL DEFAULT '';
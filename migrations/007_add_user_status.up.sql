@@ -0,0 +1,14 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'active',
+    ADD COLUMN IF NOT EXISTS status_reason TEXT,
+    ADD COLUMN IF NOT EXISTS status_expires_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_status_check;
+
+ALTER TABLE users
+    ADD CONSTRAINT users_status_check CHECK (
+        status IN ('active', 'suspended', 'banned', 'graduated')
+    );
+
+CREATE INDEX IF NOT EXISTS idx_users_dormitory_status ON users (dormitory_id, status);
@@ -0,0 +1,17 @@
+ALTER TABLE users ALTER COLUMN role TYPE VARCHAR(32);
+
+CREATE TABLE IF NOT EXISTS role_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    dormitory_id varchar(2) NOT NULL REFERENCES dormitory (id) ON DELETE CASCADE,
+    old_role VARCHAR(32) NOT NULL,
+    new_role VARCHAR(32) NOT NULL,
+    -- NULL when the change was not made by a user, e.g. from authctl
+    actor_id UUID REFERENCES users (id) ON DELETE SET NULL,
+    actor_source VARCHAR(16) NOT NULL,
+    changed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_role_changes_user ON role_changes (user_id, changed_at);
+
+CREATE INDEX IF NOT EXISTS idx_role_changes_dormitory ON role_changes (dormitory_id, changed_at);
@@ -0,0 +1,58 @@
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(64) PRIMARY KEY,
+    description TEXT NOT NULL,
+    -- permissions that only come with a role cannot be granted to a user
+    grantable BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role VARCHAR(32) NOT NULL,
+    permission VARCHAR(64) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE IF NOT EXISTS user_permissions (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL REFERENCES permissions (name) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    granted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, permission)
+);
+
+INSERT INTO
+    permissions (name, description, grantable)
+VALUES
+    ('dormitory.access', 'Доступ к сервисам общежития', FALSE),
+    ('dormitory.admin', 'Администрирование общежития', FALSE),
+    ('news.publish', 'Публикация новостей общежития', TRUE),
+    ('laundry.book', 'Бронирование прачечной', TRUE),
+    ('repairs.request', 'Подача заявок на ремонт', TRUE),
+    ('repairs.manage', 'Обработка заявок на ремонт', TRUE),
+    ('floor.manage', 'Управление этажом', TRUE),
+    ('security.checkpoint', 'Работа на пропускном пункте', TRUE)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO
+    role_permissions (role, permission)
+VALUES
+    ('student', 'dormitory.access'),
+    ('student', 'laundry.book'),
+    ('student', 'repairs.request'),
+    ('floor_warden', 'dormitory.access'),
+    ('floor_warden', 'laundry.book'),
+    ('floor_warden', 'repairs.request'),
+    ('floor_warden', 'news.publish'),
+    ('floor_warden', 'floor.manage'),
+    ('security_guard', 'dormitory.access'),
+    ('security_guard', 'security.checkpoint'),
+    ('maintenance', 'dormitory.access'),
+    ('maintenance', 'repairs.manage'),
+    ('admin', 'dormitory.access'),
+    ('admin', 'dormitory.admin'),
+    ('admin', 'news.publish'),
+    ('admin', 'laundry.book'),
+    ('admin', 'repairs.request'),
+    ('admin', 'repairs.manage'),
+    ('admin', 'floor.manage'),
+    ('admin', 'security.checkpoint')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,15 @@
+-- dormitories a user may act in besides their own
+CREATE TABLE IF NOT EXISTS user_dormitory_scopes (
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    dormitory_id varchar(2) NOT NULL REFERENCES dormitory (id) ON DELETE CASCADE,
+    granted_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    granted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, dormitory_id)
+);
+
+-- super admins act in every dormitory with every permission
+INSERT INTO
+    role_permissions (role, permission)
+SELECT 'super_admin', name
+FROM permissions
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,30 @@
+-- NULL valid_from means the grant applies at once, NULL valid_until that it
+-- never expires
+ALTER TABLE user_permissions
+ADD COLUMN IF NOT EXISTS valid_from TIMESTAMP,
+ADD COLUMN IF NOT EXISTS valid_until TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_user_permissions_valid_until ON user_permissions (valid_until)
+WHERE
+    valid_until IS NOT NULL;
+
+-- roles given for a period on top of users.role
+CREATE TABLE IF NOT EXISTS role_grants (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_id UUID NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    dormitory_id varchar(2) NOT NULL REFERENCES dormitory (id) ON DELETE CASCADE,
+    role VARCHAR(32) NOT NULL,
+    valid_from TIMESTAMP NOT NULL,
+    valid_until TIMESTAMP NOT NULL,
+    granted_by UUID REFERENCES users (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- set by the grant sweeper when the grant takes effect and ends
+    started_at TIMESTAMP,
+    expired_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_role_grants_user ON role_grants (user_id, valid_from);
+
+CREATE INDEX IF NOT EXISTS idx_role_grants_unexpired ON role_grants (valid_until)
+WHERE
+    expired_at IS NULL;
@@ -0,0 +1,14 @@
+-- log of changes that affect access decisions, read by WatchUserChanges.
+-- Rows are written under an advisory lock, so ids grow in commit order and
+-- can be used as a resume cursor. No foreign keys: the log outlives users.
+CREATE TABLE IF NOT EXISTS user_changes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    dormitory_id varchar(2) NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    old_value TEXT,
+    new_value TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_changes_dormitory ON user_changes (dormitory_id, id);
@@ -0,0 +1,19 @@
+-- domain events written in the same transaction as the change they describe
+-- and delivered to other services by the outbox relay. Rows stay after
+-- publishing so event_id can be used to trace a delivery.
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_id UUID NOT NULL UNIQUE,
+    type VARCHAR(64) NOT NULL,
+    user_id UUID NOT NULL,
+    dormitory_id varchar(2) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    published_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events (next_attempt_at, id)
+    WHERE published_at IS NULL;
@@ -0,0 +1,4 @@
+-- super admins hold every permission through the permission check itself;
+-- the copy made by 010 misses every permission added after it
+DELETE FROM role_permissions
+WHERE role = 'super_admin';
@@ -0,0 +1,85 @@
+package authclient
+
+import (
+	"context"
+
+	pb "github.com/dormitory-life/auth/proto"
+)
+
+// Register creates an account. It is not retried: a repeated call after a
+// lost response fails with FailedPrecondition.
+func (c *Client) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	var resp *pb.RegisterResponse
+	err := c.retry.once(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.rpc.Register(ctx, req)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// Login signs in with a password. It is not retried: a lost response has
+// already opened a session, and a repeated call would open another one.
+func (c *Client) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	var resp *pb.LoginResponse
+	err := c.retry.once(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.rpc.Login(ctx, req)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// RefreshTokens exchanges a refresh token. It is not retried: presenting a
+// refresh token the server already exchanged revokes the whole session.
+func (c *Client) RefreshTokens(ctx context.Context, req *pb.RefreshTokensRequest) (*pb.RefreshTokensResponse, error) {
+	var resp *pb.RefreshTokensResponse
+	err := c.retry.once(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.rpc.RefreshTokens(ctx, req)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (c *Client) Logout(ctx context.Context, refreshToken string) error {
+	return c.retry.do(ctx, func(ctx context.Context) error {
+		_, err := c.rpc.Logout(ctx, &pb.LogoutRequest{
+			RefreshToken: refreshToken,
+		})
+
+		return err
+	})
+}
+
+func (c *Client) GetUser(ctx context.Context, userId string) (*pb.GetUserResponse, error) {
+	var resp *pb.GetUserResponse
+	err := c.retry.do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.rpc.GetUser(ctx, &pb.GetUserRequest{
+			UserId: userId,
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
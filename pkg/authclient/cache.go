@@ -0,0 +1,111 @@
+package authclient
+
+import (
+	"sync"
+	"time"
+)
+
+type decisionKey struct {
+	userId       string
+	dormitoryId  string
+	roleRequired bool
+	// permission is empty for CheckAccess decisions.
+	permission string
+}
+
+type cachedDecision struct {
+	decision  Decision
+	expiresAt time.Time
+}
+
+// decisionCache is a TTL cache of access decisions. A nil cache is valid and
+// caches nothing.
+type decisionCache struct {
+	ttl     time.Duration
+	maxSize int
+
+	mu      sync.Mutex
+	entries map[decisionKey]cachedDecision
+}
+
+func newDecisionCache(ttl time.Duration, maxSize int) *decisionCache {
+	return &decisionCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		entries: make(map[decisionKey]cachedDecision),
+	}
+}
+
+func (c *decisionCache) get(key decisionKey) (*Decision, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	decision := entry.decision
+	return &decision, true
+}
+
+func (c *decisionCache) put(key decisionKey, decision *Decision) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.maxSize {
+		c.evict()
+	}
+
+	c.entries[key] = cachedDecision{
+		decision:  *decision,
+		expiresAt: time.Now().Add(c.ttl),
+	}
+}
+
+func (c *decisionCache) deleteUser(userId string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if key.userId == userId {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// evict drops expired entries and, if the cache is still full, an arbitrary
+// half of the rest. Must be called with mu held.
+func (c *decisionCache) evict() {
+	now := time.Now()
+	for key, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+	}
+
+	for key := range c.entries {
+		if len(c.entries) < c.maxSize/2+1 {
+			break
+		}
+
+		delete(c.entries, key)
+	}
+}
@@ -0,0 +1,306 @@
+// Package authclient is a client for the AuthProtoService gRPC API. It
+// retries transient failures, puts a deadline on every call and caches
+// CheckAccess, BatchCheckAccess and CheckPermission decisions for a short
+// time.
+package authclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	pb "github.com/dormitory-life/auth/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	DefaultTimeout        = 2 * time.Second
+	DefaultMaxAttempts    = 3
+	DefaultInitialBackoff = 50 * time.Millisecond
+	DefaultMaxBackoff     = time.Second
+	DefaultCacheTTL       = 5 * time.Second
+	DefaultCacheSize      = 10000
+)
+
+type Config struct {
+	// Target is the address of the auth service, e.g. "auth:50051".
+	Target string
+	// DialOptions replace the default insecure transport credentials.
+	DialOptions []grpc.DialOption
+
+	// Timeout is the deadline of a single attempt.
+	Timeout time.Duration
+	// MaxAttempts includes the first call.
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// CacheTTL is how long access decisions are reused. A negative
+	// value disables the cache.
+	CacheTTL  time.Duration
+	CacheSize int
+}
+
+type Client struct {
+	conn  *grpc.ClientConn
+	rpc   pb.AuthProtoServiceClient
+	retry retryPolicy
+	cache *decisionCache
+}
+
+// Decision is the result of an access check.
+type Decision struct {
+	Allowed bool
+	// Reason is for people; branch on DenialReason instead.
+	Reason       string
+	UserRole     string
+	DenialReason pb.DenialReason
+}
+
+func New(cfg Config) (*Client, error) {
+	if cfg.Target == "" {
+		return nil, errors.New("authclient: target is required")
+	}
+
+	dialOptions := cfg.DialOptions
+	if len(dialOptions) == 0 {
+		dialOptions = []grpc.DialOption{
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+		}
+	}
+
+	conn, err := grpc.NewClient(cfg.Target, dialOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("authclient: failed to create connection: %w", err)
+	}
+
+	c := NewWithClient(pb.NewAuthProtoServiceClient(conn), cfg)
+	c.conn = conn
+
+	return c, nil
+}
+
+// NewWithClient wraps an existing stub, e.g. one over a shared connection.
+// Close does not close connections it did not open.
+func NewWithClient(rpc pb.AuthProtoServiceClient, cfg Config) *Client {
+	c := &Client{
+		rpc:   rpc,
+		retry: newRetryPolicy(cfg),
+	}
+
+	cacheTTL := cfg.CacheTTL
+	if cacheTTL == 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+
+	if cacheTTL > 0 {
+		cacheSize := cfg.CacheSize
+		if cacheSize <= 0 {
+			cacheSize = DefaultCacheSize
+		}
+
+		c.cache = newDecisionCache(cacheTTL, cacheSize)
+	}
+
+	return c
+}
+
+func (c *Client) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+func (c *Client) CheckAccess(
+	ctx context.Context,
+	userId string,
+	dormitoryId string,
+	roleRequired bool,
+) (*Decision, error) {
+	key := decisionKey{
+		userId:       userId,
+		dormitoryId:  dormitoryId,
+		roleRequired: roleRequired,
+	}
+
+	if decision, ok := c.cache.get(key); ok {
+		return decision, nil
+	}
+
+	var resp *pb.CheckAccessResponse
+	err := c.retry.do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.rpc.CheckAccess(ctx, &pb.CheckAccessRequest{
+			UserId:       userId,
+			DormitoryId:  dormitoryId,
+			RoleRequired: roleRequired,
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	decision := &Decision{
+		Allowed:      resp.GetAllowed(),
+		Reason:       resp.GetReason(),
+		UserRole:     resp.GetUserRole(),
+		DenialReason: resp.GetDenialReason(),
+	}
+
+	c.cache.put(key, decision)
+
+	return decision, nil
+}
+
+// AccessCheck is one check of BatchCheckAccess.
+type AccessCheck struct {
+	UserId       string
+	DormitoryId  string
+	RoleRequired bool
+}
+
+// BatchCheckAccess makes many CheckAccess checks in one call. Cached
+// decisions are reused and only the rest is sent. Decisions are in the order
+// of the checks.
+func (c *Client) BatchCheckAccess(ctx context.Context, checks []AccessCheck) ([]*Decision, error) {
+	decisions := make([]*Decision, len(checks))
+
+	var (
+		missing []int
+		request = &pb.BatchCheckAccessRequest{}
+	)
+
+	for i, check := range checks {
+		key := decisionKey{
+			userId:       check.UserId,
+			dormitoryId:  check.DormitoryId,
+			roleRequired: check.RoleRequired,
+		}
+
+		if decision, ok := c.cache.get(key); ok {
+			decisions[i] = decision
+			continue
+		}
+
+		missing = append(missing, i)
+		request.Checks = append(request.Checks, &pb.CheckAccessRequest{
+			UserId:       check.UserId,
+			DormitoryId:  check.DormitoryId,
+			RoleRequired: check.RoleRequired,
+		})
+	}
+
+	if len(missing) == 0 {
+		return decisions, nil
+	}
+
+	var resp *pb.BatchCheckAccessResponse
+	err := c.retry.do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.rpc.BatchCheckAccess(ctx, request)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.GetResults()) != len(missing) {
+		return nil, fmt.Errorf("authclient: got %d results for %d checks", len(resp.GetResults()), len(missing))
+	}
+
+	for j, result := range resp.GetResults() {
+		i := missing[j]
+
+		decisions[i] = &Decision{
+			Allowed:      result.GetAllowed(),
+			Reason:       result.GetReason(),
+			UserRole:     result.GetUserRole(),
+			DenialReason: result.GetDenialReason(),
+		}
+
+		c.cache.put(decisionKey{
+			userId:       checks[i].UserId,
+			dormitoryId:  checks[i].DormitoryId,
+			roleRequired: checks[i].RoleRequired,
+		}, decisions[i])
+	}
+
+	return decisions, nil
+}
+
+// CheckPermission asks whether the user has the permission, e.g.
+// "laundry.book", inside the dormitory.
+func (c *Client) CheckPermission(
+	ctx context.Context,
+	userId string,
+	dormitoryId string,
+	permission string,
+) (*Decision, error) {
+	key := decisionKey{
+		userId:      userId,
+		dormitoryId: dormitoryId,
+		permission:  permission,
+	}
+
+	if decision, ok := c.cache.get(key); ok {
+		return decision, nil
+	}
+
+	var resp *pb.CheckPermissionResponse
+	err := c.retry.do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.rpc.CheckPermission(ctx, &pb.CheckPermissionRequest{
+			UserId:      userId,
+			DormitoryId: dormitoryId,
+			Permission:  permission,
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	decision := &Decision{
+		Allowed:      resp.GetAllowed(),
+		Reason:       resp.GetReason(),
+		UserRole:     resp.GetUserRole(),
+		DenialReason: resp.GetDenialReason(),
+	}
+
+	c.cache.put(key, decision)
+
+	return decision, nil
+}
+
+// ValidateToken asks the auth service whether an access token is valid and
+// its session is not revoked. The result is never cached.
+func (c *Client) ValidateToken(ctx context.Context, token string) (*pb.ValidateTokenResponse, error) {
+	var resp *pb.ValidateTokenResponse
+	err := c.retry.do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = c.rpc.ValidateToken(ctx, &pb.ValidateTokenRequest{
+			Token: token,
+		})
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// InvalidateUser drops the cached decisions of a user, e.g. after a role
+// change or a permission grant was observed.
+func (c *Client) InvalidateUser(userId string) {
+	c.cache.deleteUser(userId)
+}
@@ -0,0 +1,91 @@
+package authclient
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type retryPolicy struct {
+	timeout        time.Duration
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+func newRetryPolicy(cfg Config) retryPolicy {
+	policy := retryPolicy{
+		timeout:        cfg.Timeout,
+		maxAttempts:    cfg.MaxAttempts,
+		initialBackoff: cfg.InitialBackoff,
+		maxBackoff:     cfg.MaxBackoff,
+	}
+
+	if policy.timeout <= 0 {
+		policy.timeout = DefaultTimeout
+	}
+
+	if policy.maxAttempts <= 0 {
+		policy.maxAttempts = DefaultMaxAttempts
+	}
+
+	if policy.initialBackoff <= 0 {
+		policy.initialBackoff = DefaultInitialBackoff
+	}
+
+	if policy.maxBackoff <= 0 {
+		policy.maxBackoff = DefaultMaxBackoff
+	}
+
+	return policy
+}
+
+// do calls fn until it succeeds, fails with a non-retryable error, the
+// attempts run out or ctx is done. Every attempt gets its own deadline.
+func (p retryPolicy) do(ctx context.Context, fn func(ctx context.Context) error) error {
+	backoff := p.initialBackoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, p.timeout)
+		err = fn(attemptCtx)
+		cancel()
+
+		if err == nil || attempt >= p.maxAttempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		// full jitter keeps clients from retrying in lockstep
+		timer := time.NewTimer(rand.N(backoff) + 1)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, p.maxBackoff)
+	}
+}
+
+// once calls fn a single time with the attempt deadline. It is for calls
+// that must not be repeated, e.g. a refresh token exchange the server may
+// already have applied.
+func (p retryPolicy) once(ctx context.Context, fn func(ctx context.Context) error) error {
+	attemptCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	return fn(attemptCtx)
+}
+
+func retryable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
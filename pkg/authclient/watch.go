@@ -0,0 +1,41 @@
+package authclient
+
+import (
+	"context"
+
+	pb "github.com/dormitory-life/auth/proto"
+)
+
+// WatchUserChanges streams changes of users and drops the cached decisions
+// of every changed user before passing the event to handle, which may be
+// nil. It returns when ctx is done, handle fails or the stream breaks;
+// reconnect with the cursor of the last handled event to miss nothing.
+//
+// The stream has no deadline and is not retried.
+func (c *Client) WatchUserChanges(
+	ctx context.Context,
+	req *pb.WatchUserChangesRequest,
+	handle func(event *pb.UserChangeEvent) error,
+) error {
+	stream, err := c.rpc.WatchUserChanges(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		c.InvalidateUser(event.GetUserId())
+
+		if handle == nil {
+			continue
+		}
+
+		if err := handle(event); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Package authverifytest mints tokens in the auth service format for unit
+// tests of services that use authverify.
+package authverifytest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dormitory-life/auth/pkg/authverify"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultIssuer   = "dormitory-life-auth"
+	DefaultAudience = "dormitory-life"
+)
+
+// Minter signs tokens either with a shared secret (NewHS256) or with a
+// generated Ed25519 key published through a test JWKS server (NewEdDSA).
+type Minter struct {
+	Issuer   string
+	Audience string
+	TTL      time.Duration
+
+	method     jwt.SigningMethod
+	signingKey any
+	kid        string
+	secret     []byte
+	publicKey  ed25519.PublicKey
+	jwksServer *httptest.Server
+}
+
+func NewHS256(secret []byte) *Minter {
+	return &Minter{
+		Issuer:     DefaultIssuer,
+		Audience:   DefaultAudience,
+		TTL:        15 * time.Minute,
+		method:     jwt.SigningMethodHS256,
+		signingKey: secret,
+		secret:     secret,
+	}
+}
+
+// NewEdDSA generates a signing key and starts a JWKS server publishing it.
+// The server is closed when the test finishes.
+func NewEdDSA(tb testing.TB) *Minter {
+	tb.Helper()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		tb.Fatalf("authverifytest: generating key: %v", err)
+	}
+
+	m := &Minter{
+		Issuer:     DefaultIssuer,
+		Audience:   DefaultAudience,
+		TTL:        15 * time.Minute,
+		method:     jwt.SigningMethodEdDSA,
+		signingKey: privateKey,
+		kid:        uuid.NewString(),
+		publicKey:  publicKey,
+	}
+
+	m.jwksServer = httptest.NewServer(http.HandlerFunc(m.serveJWKS))
+	tb.Cleanup(m.jwksServer.Close)
+
+	return m
+}
+
+// Config returns a verifier configuration that accepts the minted tokens.
+func (m *Minter) Config() authverify.Config {
+	cfg := authverify.Config{
+		Secret:   m.secret,
+		Issuer:   m.Issuer,
+		Audience: m.Audience,
+	}
+
+	if m.jwksServer != nil {
+		cfg.JWKSURL = m.jwksServer.URL
+		cfg.HTTPClient = m.jwksServer.Client()
+	}
+
+	return cfg
+}
+
+func (m *Minter) Verifier(tb testing.TB) *authverify.Verifier {
+	tb.Helper()
+
+	verifier, err := authverify.New(m.Config())
+	if err != nil {
+		tb.Fatalf("authverifytest: creating verifier: %v", err)
+	}
+
+	return verifier
+}
+
+// Mint signs an access token for the principal. Empty session and token ids
+// are generated.
+func (m *Minter) Mint(tb testing.TB, principal authverify.Principal) string {
+	tb.Helper()
+
+	now := time.Now()
+
+	claims := &authverify.Claims{
+		UserId:      principal.UserId,
+		DormitoryId: principal.DormitoryId,
+		Role:        principal.Role,
+		Dormitories: principal.Dormitories,
+		SessionId:   principal.SessionId,
+		ClientId:    principal.ClientId,
+		Type:        authverify.TokenTypeAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    m.Issuer,
+			Subject:   principal.UserId,
+			ExpiresAt: jwt.NewNumericDate(now.Add(m.TTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ID:        principal.TokenId,
+		},
+	}
+
+	if m.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{m.Audience}
+	}
+
+	if claims.SessionId == "" {
+		claims.SessionId = uuid.NewString()
+	}
+
+	if claims.ID == "" {
+		claims.ID = uuid.NewString()
+	}
+
+	if !principal.ExpiresAt.IsZero() {
+		claims.ExpiresAt = jwt.NewNumericDate(principal.ExpiresAt)
+	}
+
+	return m.MintClaims(tb, claims)
+}
+
+// MintClaims signs the claims as they are, which allows building expired or
+// otherwise malformed tokens.
+func (m *Minter) MintClaims(tb testing.TB, claims *authverify.Claims) string {
+	tb.Helper()
+
+	token := jwt.NewWithClaims(m.method, claims)
+	if m.kid != "" {
+		token.Header["kid"] = m.kid
+	}
+
+	signed, err := token.SignedString(m.signingKey)
+	if err != nil {
+		tb.Fatalf("authverifytest: signing token: %v", err)
+	}
+
+	return signed
+}
+
+func (m *Minter) serveJWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(w).Encode(authverify.JWKSet{
+		Keys: []authverify.JWK{{
+			Kty: "OKP",
+			Use: "sig",
+			Kid: m.kid,
+			Alg: jwt.SigningMethodEdDSA.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(m.publicKey),
+		}},
+	})
+}
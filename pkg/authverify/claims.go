@@ -0,0 +1,84 @@
+// Package authverify verifies access tokens issued by the dormitory-life auth
+// service. Tokens are checked offline, against a shared HS256 secret or the
+// public keys published at /.well-known/jwks.json, so revoked sessions are
+// only noticed once the access token expires. Services that need immediate
+// revocation should call the introspection endpoint or the ValidateToken RPC.
+package authverify
+
+import (
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// RoleSuperAdmin is the role of staff that act in every dormitory.
+const RoleSuperAdmin = "super_admin"
+
+// Claims is the payload of tokens issued by the auth service.
+type Claims struct {
+	UserId      string `json:"user_id"`
+	DormitoryId string `json:"dormitory_id"`
+	// Dormitories are the dormitories the user may act in besides their own.
+	Dormitories []string `json:"dormitories,omitempty"`
+	Role        string   `json:"role,omitempty"`
+	SessionId   string   `json:"sid,omitempty"`
+	ClientId    string   `json:"client_id,omitempty"`
+	Type        string   `json:"type"`
+	jwt.RegisteredClaims
+}
+
+// Principal is the caller identified by a verified access token.
+type Principal struct {
+	UserId      string
+	DormitoryId string
+	Dormitories []string
+	Role        string
+	SessionId   string
+	ClientId    string
+	TokenId     string
+	ExpiresAt   time.Time
+}
+
+func (p *Principal) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		if p.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// InDormitory reports whether the caller may act in the dormitory: their own,
+// one of their scopes, or any of them for a super admin.
+func (p *Principal) InDormitory(dormitoryId string) bool {
+	if p.Role == RoleSuperAdmin || p.DormitoryId == dormitoryId {
+		return true
+	}
+
+	return slices.Contains(p.Dormitories, dormitoryId)
+}
+
+func (c *Claims) Principal() *Principal {
+	principal := &Principal{
+		UserId:      c.UserId,
+		DormitoryId: c.DormitoryId,
+		Dormitories: c.Dormitories,
+		Role:        c.Role,
+		SessionId:   c.SessionId,
+		ClientId:    c.ClientId,
+		TokenId:     c.ID,
+	}
+
+	if c.ExpiresAt != nil {
+		principal.ExpiresAt = c.ExpiresAt.Time
+	}
+
+	return principal
+}
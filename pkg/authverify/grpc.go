@@ -0,0 +1,81 @@
+package authverify
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor authenticates calls carrying a bearer token in the
+// "authorization" metadata. When roles are given, the caller must have one
+// of them.
+func (v *Verifier) UnaryServerInterceptor(roles ...string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ctx, err := v.authenticateIncoming(ctx, roles)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func (v *Verifier) StreamServerInterceptor(roles ...string) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := v.authenticateIncoming(ss.Context(), roles)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func (v *Verifier) authenticateIncoming(ctx context.Context, roles []string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var token string
+	for _, value := range md.Get("authorization") {
+		if t, ok := BearerToken(value); ok {
+			token = t
+			break
+		}
+	}
+
+	if token == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+
+	principal, err := v.VerifyPrincipal(ctx, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid token")
+	}
+
+	if len(roles) > 0 && !principal.HasRole(roles...) {
+		return nil, status.Error(codes.PermissionDenied, "insufficient role")
+	}
+
+	return NewContext(ctx, principal), nil
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
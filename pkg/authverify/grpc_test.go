@@ -0,0 +1,52 @@
+package authverify_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dormitory-life/auth/pkg/authverify"
+	"github.com/dormitory-life/auth/pkg/authverify/authverifytest"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	minter := authverifytest.NewHS256([]byte("test-secret-with-enough-bytes-for-hs256"))
+	interceptor := minter.Verifier(t).UnaryServerInterceptor("admin")
+
+	admin := authverify.Principal{UserId: uuid.NewString(), DormitoryId: "01", Role: "admin"}
+	student := authverify.Principal{UserId: uuid.NewString(), DormitoryId: "01", Role: "student"}
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          codes.Code
+	}{
+		{name: "no token", want: codes.Unauthenticated},
+		{name: "invalid token", authorization: "Bearer not.a.token", want: codes.Unauthenticated},
+		{name: "other role", authorization: "Bearer " + minter.Mint(t, student), want: codes.PermissionDenied},
+		{name: "role", authorization: "Bearer " + minter.Mint(t, admin), want: codes.OK},
+	}
+
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.authorization != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+		}
+
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+			principal, ok := authverify.FromContext(ctx)
+			if !ok || principal.UserId != admin.UserId {
+				t.Errorf("%s: handler principal = %+v, want %s", tt.name, principal, admin.UserId)
+			}
+
+			return nil, nil
+		})
+		if got := status.Code(err); got != tt.want {
+			t.Errorf("UnaryServerInterceptor() %s code = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package authverify
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+type errorResponse struct {
+	Error   string   `json:"error"`
+	Details []string `json:"details,omitempty"`
+}
+
+// Middleware rejects requests without a valid bearer access token and puts
+// the principal into the request context. When roles are given, the caller
+// must have one of them.
+func (v *Verifier) Middleware(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := BearerToken(r.Header.Get("Authorization"))
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer`)
+				writeError(w, http.StatusUnauthorized, "unauthorized", "Missing bearer token")
+				return
+			}
+
+			principal, err := v.VerifyPrincipal(r.Context(), token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				writeError(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if len(roles) > 0 && !principal.HasRole(roles...) {
+				writeError(w, http.StatusForbidden, "forbidden", "Insufficient role")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), principal)))
+		})
+	}
+}
+
+// BearerToken extracts the token from an "Authorization: Bearer <token>"
+// header value.
+func BearerToken(header string) (string, bool) {
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+
+	return token, token != ""
+}
+
+func writeError(w http.ResponseWriter, code int, err string, details ...string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(errorResponse{
+		Error:   err,
+		Details: details,
+	})
+}
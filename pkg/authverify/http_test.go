@@ -0,0 +1,66 @@
+package authverify_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dormitory-life/auth/pkg/authverify"
+	"github.com/dormitory-life/auth/pkg/authverify/authverifytest"
+	"github.com/google/uuid"
+)
+
+func TestMiddleware(t *testing.T) {
+	minter := authverifytest.NewEdDSA(t)
+	verifier := minter.Verifier(t)
+
+	admin := authverify.Principal{UserId: uuid.NewString(), DormitoryId: "01", Role: "admin"}
+	student := authverify.Principal{UserId: uuid.NewString(), DormitoryId: "01", Role: "student"}
+
+	tests := []struct {
+		name          string
+		authorization string
+		roles         []string
+		want          int
+		wantUserId    string
+	}{
+		{name: "no token", want: http.StatusUnauthorized},
+		{name: "not bearer", authorization: "Basic dXNlcjpwYXNz", want: http.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer not.a.token", want: http.StatusUnauthorized},
+		{name: "any role", authorization: "Bearer " + minter.Mint(t, student), want: http.StatusOK, wantUserId: student.UserId},
+		{name: "role", authorization: "bearer " + minter.Mint(t, admin), roles: []string{"admin"}, want: http.StatusOK, wantUserId: admin.UserId},
+		{name: "other role", authorization: "Bearer " + minter.Mint(t, student), roles: []string{"admin"}, want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotUserId string
+
+			handler := verifier.Middleware(tt.roles...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				principal, ok := authverify.FromContext(r.Context())
+				if !ok {
+					t.Errorf("FromContext() found no principal")
+					return
+				}
+
+				gotUserId = principal.UserId
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("Middleware() status = %d, want %d", rec.Code, tt.want)
+			}
+
+			if gotUserId != tt.wantUserId {
+				t.Errorf("Middleware() user = %q, want %q", gotUserId, tt.wantUserId)
+			}
+		})
+	}
+}
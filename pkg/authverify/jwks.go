@@ -0,0 +1,248 @@
+package authverify
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	DefaultJWKSCacheTTL       = 5 * time.Minute
+	DefaultJWKSRefreshBackoff = 30 * time.Second
+)
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+type publicKey struct {
+	alg string
+	key any
+}
+
+// PublicKey decodes the key material of the JWK.
+func (k *JWK) PublicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() {
+			return nil, fmt.Errorf("%w: RSA exponent is too large", ErrInvalidKey)
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("%w: unsupported curve %q", ErrInvalidKey, k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+
+		point := append([]byte{4}, append(x, y...)...)
+
+		key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+
+		return key, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("%w: unsupported curve %q", ErrInvalidKey, k.Crv)
+		}
+
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("%w: invalid Ed25519 key", ErrInvalidKey)
+		}
+
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("%w: unsupported key type %q", ErrInvalidKey, k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("%w: invalid RSA parameter", ErrInvalidKey)
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+// JWKSCache keeps the keys published by the auth service. The set is
+// refetched when it gets older than the TTL, or when a token carries an
+// unknown kid, but no more often than once per refresh backoff. Concurrent
+// refetches share one request, and lookups of cached keys never wait for it.
+type JWKSCache struct {
+	url            string
+	client         *http.Client
+	ttl            time.Duration
+	refreshBackoff time.Duration
+
+	refreshes singleflight.Group
+
+	mu        sync.Mutex
+	keys      map[string]publicKey
+	fetchedAt time.Time
+}
+
+func NewJWKSCache(url string, client *http.Client, ttl time.Duration) *JWKSCache {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultJWKSCacheTTL
+	}
+
+	return &JWKSCache{
+		url:            url,
+		client:         client,
+		ttl:            ttl,
+		refreshBackoff: min(DefaultJWKSRefreshBackoff, ttl),
+	}
+}
+
+func (c *JWKSCache) lookup(ctx context.Context, kid string) (publicKey, error) {
+	c.mu.Lock()
+	key, ok := c.keys[kid]
+	fetchedAt := c.fetchedAt
+	c.mu.Unlock()
+
+	now := time.Now()
+
+	stale := now.Sub(fetchedAt) > c.ttl
+	if ok && !stale {
+		return key, nil
+	}
+
+	if stale || now.Sub(fetchedAt) > c.refreshBackoff {
+		keys, err := c.refresh(ctx)
+		if err != nil {
+			// keep serving the previous set while the auth service is unreachable
+			if ok {
+				return key, nil
+			}
+
+			return publicKey{}, err
+		}
+
+		key, ok = keys[kid]
+	}
+
+	if !ok {
+		return publicKey{}, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+
+	return key, nil
+}
+
+// refresh fetches the set once for every lookup asking at the same time and
+// stores it. The request outlives the cancellation of the lookup that
+// started it, since other lookups may be waiting for it; the timeout of the
+// client bounds it.
+func (c *JWKSCache) refresh(ctx context.Context) (map[string]publicKey, error) {
+	result, err, _ := c.refreshes.Do(c.url, func() (any, error) {
+		keys, err := c.fetch(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		c.mu.Lock()
+		c.keys = keys
+		c.fetchedAt = time.Now()
+		c.mu.Unlock()
+
+		return keys, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(map[string]publicKey), nil
+}
+
+func (c *JWKSCache) fetch(ctx context.Context) (map[string]publicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build JWKS request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var keySet JWKSet
+	if err := json.NewDecoder(resp.Body).Decode(&keySet); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]publicKey, len(keySet.Keys))
+	for _, jwk := range keySet.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			continue
+		}
+
+		keys[jwk.Kid] = publicKey{
+			alg: jwk.Alg,
+			key: key,
+		}
+	}
+
+	return keys, nil
+}
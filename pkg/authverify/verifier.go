@@ -0,0 +1,139 @@
+package authverify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrInvalidKey   = errors.New("invalid key")
+	ErrUnknownKey   = errors.New("unknown signing key")
+)
+
+type Config struct {
+	// Secret verifies HS256 tokens. Either Secret or JWKSURL must be set.
+	Secret []byte
+	// JWKSURL is the /.well-known/jwks.json endpoint of the auth service,
+	// used for RS256, ES256 and EdDSA tokens.
+	JWKSURL      string
+	JWKSCacheTTL time.Duration
+	HTTPClient   *http.Client
+
+	// Issuer and Audience are checked when set.
+	Issuer   string
+	Audience string
+	// Leeway allows for clock skew when checking expiry.
+	Leeway time.Duration
+}
+
+type Verifier struct {
+	secret []byte
+	jwks   *JWKSCache
+	parser *jwt.Parser
+}
+
+func New(cfg Config) (*Verifier, error) {
+	if len(cfg.Secret) == 0 && cfg.JWKSURL == "" {
+		return nil, errors.New("authverify: secret or JWKS URL is required")
+	}
+
+	v := &Verifier{
+		secret: cfg.Secret,
+	}
+
+	methods := []string{jwt.SigningMethodHS256.Alg()}
+	if cfg.JWKSURL != "" {
+		v.jwks = NewJWKSCache(cfg.JWKSURL, cfg.HTTPClient, cfg.JWKSCacheTTL)
+		methods = append(methods,
+			jwt.SigningMethodRS256.Alg(),
+			jwt.SigningMethodES256.Alg(),
+			jwt.SigningMethodEdDSA.Alg(),
+		)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithExpirationRequired(),
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+
+	v.parser = jwt.NewParser(options...)
+
+	return v, nil
+}
+
+// Verify checks the signature, expiry, issuer and audience of an access
+// token and returns its claims.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	claims := &Claims{}
+
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		return v.key(ctx, t)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if claims.Type != TokenTypeAccess {
+		return nil, fmt.Errorf("%w: unexpected token type %q", ErrInvalidToken, claims.Type)
+	}
+
+	if claims.UserId == "" {
+		return nil, fmt.Errorf("%w: token has no user", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// VerifyPrincipal is Verify returning the caller instead of the raw claims.
+func (v *Verifier) VerifyPrincipal(ctx context.Context, token string) (*Principal, error) {
+	claims, err := v.Verify(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims.Principal(), nil
+}
+
+func (v *Verifier) key(ctx context.Context, token *jwt.Token) (any, error) {
+	alg := token.Method.Alg()
+
+	if alg == jwt.SigningMethodHS256.Alg() {
+		if len(v.secret) == 0 {
+			return nil, fmt.Errorf("%w: HS256 tokens are not accepted", ErrUnknownKey)
+		}
+
+		return v.secret, nil
+	}
+
+	if v.jwks == nil {
+		return nil, fmt.Errorf("%w: no JWKS configured for %s", ErrUnknownKey, alg)
+	}
+
+	kid, _ := token.Header["kid"].(string)
+
+	key, err := v.jwks.lookup(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if key.alg != "" && key.alg != alg {
+		return nil, fmt.Errorf("unexpected signing method %q for key %q", alg, kid)
+	}
+
+	return key.key, nil
+}
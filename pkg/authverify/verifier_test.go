@@ -0,0 +1,147 @@
+package authverify_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dormitory-life/auth/pkg/authverify"
+	"github.com/dormitory-life/auth/pkg/authverify/authverifytest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestVerifyPrincipalRoundTrip(t *testing.T) {
+	minters := map[string]*authverifytest.Minter{
+		"HS256": authverifytest.NewHS256([]byte("test-secret-with-enough-bytes-for-hs256")),
+		"EdDSA": authverifytest.NewEdDSA(t),
+	}
+
+	for name, minter := range minters {
+		t.Run(name, func(t *testing.T) {
+			want := authverify.Principal{
+				UserId:      uuid.NewString(),
+				DormitoryId: "01",
+				Dormitories: []string{"02", "03"},
+				Role:        "floor_warden",
+				SessionId:   uuid.NewString(),
+				ClientId:    "mobile",
+				TokenId:     uuid.NewString(),
+			}
+
+			token := minter.Mint(t, want)
+
+			got, err := minter.Verifier(t).VerifyPrincipal(context.Background(), token)
+			if err != nil {
+				t.Fatalf("VerifyPrincipal() error = %v", err)
+			}
+
+			if got.UserId != want.UserId || got.DormitoryId != want.DormitoryId || got.Role != want.Role ||
+				got.SessionId != want.SessionId || got.ClientId != want.ClientId || got.TokenId != want.TokenId {
+				t.Errorf("VerifyPrincipal() = %+v, want %+v", got, want)
+			}
+
+			if !slices.Equal(got.Dormitories, want.Dormitories) {
+				t.Errorf("VerifyPrincipal() dormitories = %v, want %v", got.Dormitories, want.Dormitories)
+			}
+
+			if got.ExpiresAt.IsZero() {
+				t.Errorf("VerifyPrincipal() has no expiry")
+			}
+
+			if !got.InDormitory("03") || got.InDormitory("04") {
+				t.Errorf("InDormitory() does not follow the dormitories of the token")
+			}
+		})
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	minter := authverifytest.NewHS256([]byte("test-secret-with-enough-bytes-for-hs256"))
+	other := authverifytest.NewHS256([]byte("another-secret-with-enough-bytes-for-hs256"))
+	eddsa := authverifytest.NewEdDSA(t)
+
+	userId := uuid.NewString()
+	now := time.Now()
+
+	claims := func(modify func(c *authverify.Claims)) *authverify.Claims {
+		c := &authverify.Claims{
+			UserId:      userId,
+			DormitoryId: "01",
+			Type:        authverify.TokenTypeAccess,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    authverifytest.DefaultIssuer,
+				Audience:  jwt.ClaimStrings{authverifytest.DefaultAudience},
+				Subject:   userId,
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+				IssuedAt:  jwt.NewNumericDate(now),
+			},
+		}
+		modify(c)
+
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{
+			name:  "expired",
+			token: minter.Mint(t, authverify.Principal{UserId: userId, ExpiresAt: now.Add(-time.Minute)}),
+		},
+		{
+			name:  "other secret",
+			token: other.Mint(t, authverify.Principal{UserId: userId}),
+		},
+		{
+			name:  "key not in the verifier config",
+			token: eddsa.Mint(t, authverify.Principal{UserId: userId}),
+		},
+		{
+			name:  "refresh token",
+			token: minter.MintClaims(t, claims(func(c *authverify.Claims) { c.Type = authverify.TokenTypeRefresh })),
+		},
+		{
+			name:  "no user",
+			token: minter.MintClaims(t, claims(func(c *authverify.Claims) { c.UserId = "" })),
+		},
+		{
+			name:  "no expiry",
+			token: minter.MintClaims(t, claims(func(c *authverify.Claims) { c.ExpiresAt = nil })),
+		},
+		{
+			name:  "other issuer",
+			token: minter.MintClaims(t, claims(func(c *authverify.Claims) { c.Issuer = "someone-else" })),
+		},
+		{
+			name:  "other audience",
+			token: minter.MintClaims(t, claims(func(c *authverify.Claims) { c.Audience = jwt.ClaimStrings{"someone-else"} })),
+		},
+		{
+			name:  "garbage",
+			token: "not.a.token",
+		},
+	}
+
+	verifier := minter.Verifier(t)
+
+	for _, tt := range tests {
+		if _, err := verifier.Verify(context.Background(), tt.token); !errors.Is(err, authverify.ErrInvalidToken) {
+			t.Errorf("Verify() %s error = %v, want %v", tt.name, err, authverify.ErrInvalidToken)
+		}
+	}
+}
+
+func TestVerifyEdDSARejectsHS256(t *testing.T) {
+	minter := authverifytest.NewEdDSA(t)
+	forged := authverifytest.NewHS256([]byte("test-secret-with-enough-bytes-for-hs256"))
+
+	token := forged.Mint(t, authverify.Principal{UserId: uuid.NewString()})
+
+	if _, err := minter.Verifier(t).Verify(context.Background(), token); !errors.Is(err, authverify.ErrInvalidToken) {
+		t.Errorf("Verify() error = %v, want %v", err, authverify.ErrInvalidToken)
+	}
+}